@@ -0,0 +1,207 @@
+// Command errcodes writes the errs code catalog into the OpenAPI spec and the @boil/zod package
+// so clients always see the same list of codes the backend can emit.
+//
+//	go run ./cmd/errcodes
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/Mayank85Y/boil/internal/openapi"
+)
+
+func main() {
+	openAPIPaths := flag.String("openapi", "../../packages/openapi/openapi.json",
+		"comma separated OpenAPI documents to update")
+	zodPath := flag.String("zod", "../../packages/zod/src/error-codes.ts", "generated TypeScript output")
+	flag.Parse()
+
+	catalog := errs.Catalog()
+
+	for _, path := range strings.Split(*openAPIPaths, ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		if err := writeOpenAPI(path, catalog); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to update %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		fmt.Printf("updated %s\n", path)
+	}
+
+	if err := os.WriteFile(*zodPath, []byte(renderZod(catalog)), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write %s: %v\n", *zodPath, err)
+		os.Exit(1)
+	}
+	fmt.Printf("wrote %s (%d codes)\n", *zodPath, len(catalog))
+}
+
+// writeOpenAPI merges the error schemas into components.schemas, leaving everything else untouched
+func writeOpenAPI(path string, catalog []errs.CodeInfo) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	doc := &orderedObject{}
+	if err := json.Unmarshal(raw, doc); err != nil {
+		return fmt.Errorf("failed to parse document: %w", err)
+	}
+
+	components := &orderedObject{}
+	if err := components.unmarshalField(doc, "components"); err != nil {
+		return err
+	}
+	schemas := &orderedObject{}
+	if err := schemas.unmarshalField(components, "schemas"); err != nil {
+		return err
+	}
+
+	newSchemas := openapi.ErrorSchemas(catalog)
+	names := make([]string, 0, len(newSchemas))
+	for name := range newSchemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := schemas.set(name, newSchemas[name]); err != nil {
+			return err
+		}
+	}
+	if err := components.set("schemas", schemas); err != nil {
+		return err
+	}
+	if err := doc.set("components", components); err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0o644)
+}
+
+// orderedObject is a JSON object that keeps its key order so regenerating doesn't reshuffle the spec
+type orderedObject struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+func (o *orderedObject) UnmarshalJSON(data []byte) error {
+	o.keys = nil
+	o.values = map[string]json.RawMessage{}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("unexpected object key %v", token)
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		if _, exists := o.values[key]; !exists {
+			o.keys = append(o.keys, key)
+		}
+		o.values[key] = value
+	}
+	return nil
+}
+
+func (o *orderedObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(encodedKey)
+		b.WriteByte(':')
+		b.Write(o.values[key])
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+func (o *orderedObject) set(key string, value any) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if o.values == nil {
+		o.values = map[string]json.RawMessage{}
+	}
+	if _, exists := o.values[key]; !exists {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = encoded
+	return nil
+}
+
+// unmarshalField decodes parent[key] into o, leaving o empty when the key is missing
+func (o *orderedObject) unmarshalField(parent *orderedObject, key string) error {
+	raw, ok := parent.values[key]
+	if !ok {
+		o.values = map[string]json.RawMessage{}
+		return nil
+	}
+	if err := json.Unmarshal(raw, o); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", key, err)
+	}
+	return nil
+}
+
+func renderZod(catalog []errs.CodeInfo) string {
+	exact, patterns := openapi.SplitCatalog(catalog)
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by apps/backend/cmd/errcodes. DO NOT EDIT.\n\n")
+	b.WriteString("import { z } from \"zod\";\n\n")
+
+	b.WriteString("export const ErrorCatalog = [\n")
+	for _, info := range catalog {
+		fmt.Fprintf(&b, "  {\n    code: %s,\n    status: %d,\n    description: %s,\n    overridable: %t,\n  },\n",
+			strconv.Quote(info.Code), info.Status, strconv.Quote(info.Description), info.Overridable)
+	}
+	b.WriteString("] as const;\n\n")
+
+	b.WriteString("export const ZErrorCode = ")
+	enum := "z.enum([\n"
+	for _, code := range exact {
+		enum += "    " + strconv.Quote(code) + ",\n"
+	}
+	enum += "  ])"
+	if len(patterns) == 0 {
+		b.WriteString(strings.ReplaceAll(enum, "\n    ", "\n  ") + ";\n\n")
+	} else {
+		b.WriteString("z.union([\n  " + enum + ",\n")
+		for _, pattern := range patterns {
+			fmt.Fprintf(&b, "  z.string().regex(/%s/),\n", pattern)
+		}
+		b.WriteString("]);\n\n")
+	}
+
+	b.WriteString("export type ErrorCode = z.infer<typeof ZErrorCode>;\n")
+	return b.String()
+}
@@ -0,0 +1,44 @@
+// Command openapi writes the OpenAPI document generated from the registered routes,
+// the same document the server serves at /openapi.json.
+//
+//	go run ./cmd/openapi
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Mayank85Y/boil/internal/handler"
+	"github.com/Mayank85Y/boil/internal/router"
+	"github.com/labstack/echo/v4"
+)
+
+func main() {
+	outPaths := flag.String("out", "static/openapi.json",
+		"comma separated files to write")
+	flag.Parse()
+
+	// handlers are only referenced, never called, so they need no server
+	routes := handler.NewRoutes(echo.New(), router.NewSpec(), nil)
+	router.RegisterRoutes(routes, handler.NewHandlers(nil, nil))
+
+	body, err := routes.Spec().JSON()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to render OpenAPI document: %v\n", err)
+		os.Exit(1)
+	}
+	body = append(body, '\n')
+
+	for _, path := range strings.Split(*outPaths, ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		if err := os.WriteFile(path, body, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		fmt.Printf("wrote %s\n", path)
+	}
+}
@@ -0,0 +1,96 @@
+// Command routes lists every mounted route with the middlewares its RouteSpec declares.
+//
+//	go run ./cmd/routes
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Mayank85Y/boil/internal/handler"
+	"github.com/Mayank85Y/boil/internal/router"
+	"github.com/labstack/echo/v4"
+)
+
+func main() {
+	// handlers are only referenced, never called, so they need no server
+	routes := handler.NewRoutes(echo.New(), nil, nil)
+	router.RegisterRoutes(routes, handler.NewHandlers(nil, nil))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tOPERATION\tSTATUS\tAUTH\tRATE LIMIT\tTIMEOUT\tBODY LIMIT\tIDEMPOTENCY\tCACHE")
+	for _, route := range routes.Table() {
+		spec := route.Spec
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			route.Method,
+			route.Path,
+			orDash(spec.OperationID),
+			route.Status,
+			auth(spec),
+			rateLimit(spec),
+			timeout(spec),
+			bodyLimit(spec),
+			idempotency(spec),
+			cache(spec),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write routes: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func auth(spec handler.RouteSpec) string {
+	switch {
+	case len(spec.Permissions) > 0:
+		return strings.Join(spec.Permissions, ",")
+	case spec.RequiresAuth():
+		return "required"
+	default:
+		return "-"
+	}
+}
+
+func rateLimit(spec handler.RouteSpec) string {
+	if spec.RateLimit == nil {
+		return "default"
+	}
+	return spec.RateLimit.String()
+}
+
+func timeout(spec handler.RouteSpec) string {
+	if spec.Timeout == 0 {
+		return "-"
+	}
+	return spec.Timeout.String()
+}
+
+func bodyLimit(spec handler.RouteSpec) string {
+	if spec.MaxBodyBytes == 0 {
+		return "default"
+	}
+	return fmt.Sprintf("%d B", spec.MaxBodyBytes)
+}
+
+func idempotency(spec handler.RouteSpec) string {
+	if spec.Idempotency == nil {
+		return "-"
+	}
+	return spec.Idempotency.String()
+}
+
+func cache(spec handler.RouteSpec) string {
+	if spec.Cache == nil {
+		return "-"
+	}
+	return spec.Cache.String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
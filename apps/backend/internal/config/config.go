@@ -18,6 +18,7 @@ type Config struct{
 	Auth			AuthConfig				`koanf:"auth" validate:"required"`
 	Redis			RedisConfig				`koanf:"redis" validate:"required"`
 	Integration 	IntegrationConfig		`koanf:"integration" validate:"required"`
+	Storage			StorageConfig			`koanf:"storage"`
 	Observability	*ObservabilityConfig 	`koanf:"observability"`
 }
 
@@ -25,6 +26,10 @@ type Primary struct{
 	Env string `koanf:"env" validate:"required"` //`` is go struct tags heelp in reflection help some metadaata
 }
 
+func (p Primary) IsProduction() bool {
+	return p.Env == "production"
+}
+
 type ServerConfig struct {
 	Port				string	 `koanf:"port" validate:"required"`	
 	ReadTimeout			int		 `koanf:"read_timeout" validate:"required"`
@@ -47,7 +52,8 @@ type DatabaseConfig struct {
 }
 
 type AuthConfig struct {
-	SecretKey string `koanf:"secret_key" validated:"required"`
+	SecretKey 	string `koanf:"secret_key" validated:"required"`
+	DebugSecret	string `koanf:"debug_secret"` //signs X-Debug-Token, debug tokens are rejected when empty
 }
 
 type RedisConfig struct {
@@ -58,6 +64,21 @@ type IntegrationConfig struct{
 	ResendAPIKey string `koanf:"resend_api_key" validate:"required"`
 }
 
+type StorageConfig struct {
+	Driver		string			`koanf:"driver" validate:"omitempty,oneof=local s3"` //local when empty
+	LocalPath	string			`koanf:"local_path"` //root directory of the local driver, defaults to ./uploads
+	S3			S3StorageConfig	`koanf:"s3"`
+}
+
+type S3StorageConfig struct {
+	Endpoint		string	`koanf:"endpoint"`
+	Region			string	`koanf:"region"`
+	Bucket			string	`koanf:"bucket"`
+	AccessKeyID		string	`koanf:"access_key_id"`
+	SecretAccessKey	string	`koanf:"secret_access_key"`
+	UseSSL			bool	`koanf:"use_ssl"`
+}
+
 func LoadConfig() (*Config, error){
 	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().Timestamp().Logger()
 
@@ -81,6 +102,10 @@ func LoadConfig() (*Config, error){
 		logger.Fatal().Err(err).Msg("config validation failed")
 	}
 
+	if mainConfig.Storage.LocalPath == "" {
+		mainConfig.Storage.LocalPath = "uploads"
+	}
+
 	if mainConfig.Observability == nil {
 		mainConfig.Observability = DefaultObservabilityConfig()
 	}
@@ -0,0 +1,173 @@
+package errs
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RedirectPayload sends the client to another route
+type RedirectPayload struct {
+	URL string `json:"url"`
+}
+
+// ReauthenticatePayload asks the client to sign in again
+type ReauthenticatePayload struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// RetryPayload tells the client the same request can be sent again after a delay
+type RetryPayload struct {
+	AfterSeconds int64 `json:"afterSeconds"`
+}
+
+// UpgradePlanPayload points the user at the plan that unlocks a feature
+type UpgradePlanPayload struct {
+	RequiredPlan string `json:"requiredPlan"`
+	Feature      string `json:"feature,omitempty"`
+	URL          string `json:"url,omitempty"`
+}
+
+// RefreshPayload tells the client its copy of a resource is stale and must be fetched again
+type RefreshPayload struct {
+	Resource string `json:"resource"`
+	ID       string `json:"id,omitempty"`
+}
+
+// ContactSupportPayload gives the user a way to reach support, Reference is usually the request id
+type ContactSupportPayload struct {
+	Email     string `json:"email,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Reference string `json:"reference,omitempty"`
+}
+
+// ActionTypes lists every supported action type, used for docs and validation
+func ActionTypes() []ActionType {
+	return []ActionType{
+		ActionTypeRedirect,
+		ActionTypeReauthenticate,
+		ActionTypeRetry,
+		ActionTypeUpgradePlan,
+		ActionTypeRefresh,
+		ActionTypeContactSupport,
+	}
+}
+
+func NewRedirectAction(message string, url string) *Action {
+	return &Action{
+		Type:    ActionTypeRedirect,
+		Message: message,
+		Value:   url,
+		Payload: RedirectPayload{URL: url},
+	}
+}
+
+func NewReauthenticateAction(message string, reason string) *Action {
+	return &Action{
+		Type:    ActionTypeReauthenticate,
+		Message: message,
+		Value:   reason,
+		Payload: ReauthenticatePayload{Reason: reason},
+	}
+}
+
+// after is rounded up to whole seconds
+func NewRetryAction(message string, after time.Duration) *Action {
+	seconds := retryAfterSeconds(after)
+	return &Action{
+		Type:    ActionTypeRetry,
+		Message: message,
+		Value:   strconv.FormatInt(seconds, 10),
+		Payload: RetryPayload{AfterSeconds: seconds},
+	}
+}
+
+func NewUpgradePlanAction(message string, payload UpgradePlanPayload) *Action {
+	return &Action{
+		Type:    ActionTypeUpgradePlan,
+		Message: message,
+		Value:   payload.RequiredPlan,
+		Payload: payload,
+	}
+}
+
+func NewRefreshAction(message string, resource string, id string) *Action {
+	return &Action{
+		Type:    ActionTypeRefresh,
+		Message: message,
+		Value:   resource,
+		Payload: RefreshPayload{Resource: resource, ID: id},
+	}
+}
+
+func NewContactSupportAction(message string, payload ContactSupportPayload) *Action {
+	value := payload.Email
+	if value == "" {
+		value = payload.URL
+	}
+	return &Action{
+		Type:    ActionTypeContactSupport,
+		Message: message,
+		Value:   value,
+		Payload: payload,
+	}
+}
+
+// Validate checks that the type is known and the payload matches it.
+// A nil payload is accepted for actions built by hand before payloads existed.
+func (a *Action) Validate() error {
+	switch a.Type {
+	case ActionTypeRedirect:
+		if p, ok := a.Payload.(RedirectPayload); ok && p.URL == "" {
+			return fmt.Errorf("errs: %s action requires a url", a.Type)
+		}
+		if a.Payload == nil && a.Value == "" {
+			return fmt.Errorf("errs: %s action requires a url", a.Type)
+		}
+		return checkPayload[RedirectPayload](a)
+	case ActionTypeReauthenticate:
+		return checkPayload[ReauthenticatePayload](a)
+	case ActionTypeRetry:
+		if p, ok := a.Payload.(RetryPayload); ok && p.AfterSeconds < 0 {
+			return fmt.Errorf("errs: %s action requires a non negative delay", a.Type)
+		}
+		return checkPayload[RetryPayload](a)
+	case ActionTypeUpgradePlan:
+		if p, ok := a.Payload.(UpgradePlanPayload); ok && p.RequiredPlan == "" {
+			return fmt.Errorf("errs: %s action requires a plan", a.Type)
+		}
+		return checkPayload[UpgradePlanPayload](a)
+	case ActionTypeRefresh:
+		if p, ok := a.Payload.(RefreshPayload); ok && p.Resource == "" {
+			return fmt.Errorf("errs: %s action requires a resource", a.Type)
+		}
+		return checkPayload[RefreshPayload](a)
+	case ActionTypeContactSupport:
+		return checkPayload[ContactSupportPayload](a)
+	default:
+		return fmt.Errorf("errs: unknown action type %q", a.Type)
+	}
+}
+
+// checkPayload makes sure a non nil payload has the struct type belonging to the action
+func checkPayload[P any](a *Action) error {
+	if a.Payload == nil {
+		return nil
+	}
+	if _, ok := a.Payload.(P); !ok {
+		var want P
+		return fmt.Errorf("errs: %s action expects payload %T, got %T", a.Type, want, a.Payload)
+	}
+	return nil
+}
+
+func retryAfterSeconds(after time.Duration) int64 {
+	seconds := int64(after / time.Second)
+	if after%time.Second != 0 {
+		seconds++
+	}
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
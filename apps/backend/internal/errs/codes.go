@@ -0,0 +1,228 @@
+package errs
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// EntityPlaceholder marks the variable part of a code family, e.g. {ENTITY}_NOT_FOUND
+// matches USER_NOT_FOUND and ORDER_ITEM_NOT_FOUND.
+const EntityPlaceholder = "{ENTITY}"
+
+// entityToken is what EntityPlaceholder stands for: upper case words joined by single underscores
+const entityToken = `[A-Z][A-Z0-9]*(?:_[A-Z0-9]+)*`
+
+// entities for errors that can't name a more specific one
+const (
+	EntityRecord   = "RECORD"
+	EntityResource = "RESOURCE"
+)
+
+// generic codes, one per HTTP status we emit
+const (
+	CodeBadRequest           = "BAD_REQUEST"
+	CodeUnauthorized         = "UNAUTHORIZED"
+	CodeForbidden            = "FORBIDDEN"
+	CodeNotFound             = "NOT_FOUND"
+	CodeMethodNotAllowed     = "METHOD_NOT_ALLOWED"
+	CodeNotAcceptable        = "NOT_ACCEPTABLE"
+	CodeRequestTimeout       = "REQUEST_TIMEOUT"
+	CodeConflict             = "CONFLICT"
+	CodeGone                 = "GONE"
+	CodePreconditionFailed   = "PRECONDITION_FAILED"
+	CodeRequestTooLarge      = "REQUEST_ENTITY_TOO_LARGE"
+	CodeUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+	CodeUnprocessableEntity  = "UNPROCESSABLE_ENTITY"
+	CodeTooManyRequests      = "TOO_MANY_REQUESTS"
+	CodeInternalServerError  = "INTERNAL_SERVER_ERROR"
+	CodeNotImplemented       = "NOT_IMPLEMENTED"
+	CodeBadGateway           = "BAD_GATEWAY"
+	CodeServiceUnavailable   = "SERVICE_UNAVAILABLE"
+	CodeGatewayTimeout       = "GATEWAY_TIMEOUT"
+)
+
+// database codes emitted by sqlerr
+const (
+	CodeEntityNotFound      = EntityPlaceholder + "_NOT_FOUND"
+	CodeEntityAlreadyExists = EntityPlaceholder + "_ALREADY_EXISTS"
+	CodeEntityRequired      = EntityPlaceholder + "_REQUIRED"
+	CodeEntityInvalid       = EntityPlaceholder + "_INVALID"
+	CodeEntityConflict      = EntityPlaceholder + "_CONFLICT"
+	CodeValueTooLong        = "VALUE_TOO_LONG"
+	CodeInvalidFormat       = "INVALID_FORMAT"
+	CodeInvalidData         = "INVALID_DATA"
+	CodeTransactionConflict = "TRANSACTION_CONFLICT"
+	CodeResourceLocked      = "RESOURCE_LOCKED"
+	CodeQueryTimeout        = "QUERY_TIMEOUT"
+)
+
+// idempotency codes emitted by middleware.Idempotency
+const (
+	CodeIdempotencyKeyRequired = "IDEMPOTENCY_KEY_REQUIRED"
+	CodeIdempotencyKeyInvalid  = "IDEMPOTENCY_KEY_INVALID"
+	CodeIdempotencyKeyInUse    = "IDEMPOTENCY_KEY_IN_USE"
+	CodeIdempotencyKeyReused   = "IDEMPOTENCY_KEY_REUSED"
+)
+
+// CodeInfo documents an error code clients can receive
+type CodeInfo struct {
+	Code        string `json:"code"`
+	Status      int    `json:"status"`
+	Description string `json:"description"`
+	// Overridable tells clients the message is safe to show to end users as is
+	Overridable bool `json:"overridable"`
+}
+
+// IsPattern reports whether the code is a family containing EntityPlaceholder
+func (ci CodeInfo) IsPattern() bool {
+	return strings.Contains(ci.Code, EntityPlaceholder)
+}
+
+type codeCatalog struct {
+	mu       sync.RWMutex
+	codes    map[string]CodeInfo
+	patterns map[string]*regexp.Regexp
+}
+
+var catalog = newCodeCatalog(
+	CodeInfo{CodeBadRequest, http.StatusBadRequest, "The request is malformed or failed validation", false},
+	CodeInfo{CodeUnauthorized, http.StatusUnauthorized, "Authentication is missing or invalid", false},
+	CodeInfo{CodeForbidden, http.StatusForbidden, "The caller is not allowed to perform this action", false},
+	CodeInfo{CodeNotFound, http.StatusNotFound, "The route or resource does not exist", false},
+	CodeInfo{CodeMethodNotAllowed, http.StatusMethodNotAllowed, "The route does not support this HTTP method", false},
+	CodeInfo{CodeNotAcceptable, http.StatusNotAcceptable, "None of the requested representations are available", false},
+	CodeInfo{CodeRequestTimeout, http.StatusRequestTimeout, "The request took too long to be received", false},
+	CodeInfo{CodeConflict, http.StatusConflict, "The request conflicts with the current state of the resource", false},
+	CodeInfo{CodeGone, http.StatusGone, "The resource is no longer available", false},
+	CodeInfo{CodePreconditionFailed, http.StatusPreconditionFailed, "A request precondition such as If-Match failed", false},
+	CodeInfo{CodeRequestTooLarge, http.StatusRequestEntityTooLarge, "The request body exceeds the allowed size", false},
+	CodeInfo{CodeUnsupportedMediaType, http.StatusUnsupportedMediaType, "The request content type is not supported", false},
+	CodeInfo{CodeUnprocessableEntity, http.StatusUnprocessableEntity, "The request is well formed but semantically invalid", false},
+	CodeInfo{CodeTooManyRequests, http.StatusTooManyRequests, "Rate limit exceeded", false},
+	CodeInfo{CodeInternalServerError, http.StatusInternalServerError, "An unexpected error occurred", false},
+	CodeInfo{CodeNotImplemented, http.StatusNotImplemented, "The functionality is not implemented", false},
+	CodeInfo{CodeBadGateway, http.StatusBadGateway, "An upstream service returned an invalid response", false},
+	CodeInfo{CodeServiceUnavailable, http.StatusServiceUnavailable, "The service is temporarily unavailable", true},
+	CodeInfo{CodeGatewayTimeout, http.StatusGatewayTimeout, "An upstream service did not respond in time", false},
+
+	CodeInfo{CodeEntityNotFound, http.StatusNotFound, "The requested entity does not exist", true},
+	CodeInfo{CodeEntityAlreadyExists, http.StatusBadRequest, "A unique constraint on the entity was violated", true},
+	CodeInfo{CodeEntityRequired, http.StatusBadRequest, "A required column of the entity is missing", true},
+	CodeInfo{CodeEntityInvalid, http.StatusBadRequest, "A check constraint on the entity was violated", true},
+	CodeInfo{CodeEntityConflict, http.StatusConflict, "An exclusion constraint on the entity was violated", true},
+	CodeInfo{CodeValueTooLong, http.StatusBadRequest, "A value is too long for its column", true},
+	CodeInfo{CodeInvalidFormat, http.StatusBadRequest, "A value could not be parsed into its column type", true},
+	CodeInfo{CodeInvalidData, http.StatusBadRequest, "A value was rejected by the database", true},
+	CodeInfo{CodeTransactionConflict, http.StatusConflict, "A concurrent update caused the transaction to fail, retry the request", true},
+	CodeInfo{CodeResourceLocked, http.StatusConflict, "The resource is locked by another request, retry the request", true},
+	CodeInfo{CodeQueryTimeout, http.StatusServiceUnavailable, "The database query took too long", true},
+
+	CodeInfo{CodeIdempotencyKeyRequired, http.StatusBadRequest, "The route requires an Idempotency-Key header", true},
+	CodeInfo{CodeIdempotencyKeyInvalid, http.StatusBadRequest, "The Idempotency-Key header is too long or contains invalid characters", true},
+	CodeInfo{CodeIdempotencyKeyInUse, http.StatusConflict, "A request with the same Idempotency-Key is still in progress, retry the request", true},
+	CodeInfo{CodeIdempotencyKeyReused, http.StatusUnprocessableEntity, "The Idempotency-Key was already used for a different request", true},
+)
+
+func newCodeCatalog(infos ...CodeInfo) *codeCatalog {
+	c := &codeCatalog{
+		codes:    map[string]CodeInfo{},
+		patterns: map[string]*regexp.Regexp{},
+	}
+	for _, info := range infos {
+		if err := c.register(info); err != nil {
+			panic(err)
+		}
+	}
+	return c
+}
+
+func (c *codeCatalog) register(info CodeInfo) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if info.Code == "" {
+		return fmt.Errorf("errs: error code must not be empty")
+	}
+	if existing, ok := c.codes[info.Code]; ok {
+		if existing == info {
+			return nil
+		}
+		return fmt.Errorf("errs: error code %q registered twice with different definitions", info.Code)
+	}
+
+	c.codes[info.Code] = info
+	if info.IsPattern() {
+		c.patterns[info.Code] = regexp.MustCompile(EntityPattern(info.Code))
+	}
+	return nil
+}
+
+func (c *codeCatalog) lookup(code string) (CodeInfo, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if info, ok := c.codes[code]; ok {
+		return info, true
+	}
+	for pattern, re := range c.patterns {
+		if re.MatchString(code) {
+			return c.codes[pattern], true
+		}
+	}
+	return CodeInfo{}, false
+}
+
+// RegisterCode adds a code to the catalog. Registering the same definition twice is a no-op,
+// registering a different definition for an existing code panics.
+func RegisterCode(info CodeInfo) {
+	if err := catalog.register(info); err != nil {
+		panic(err)
+	}
+}
+
+// LookupCode returns the catalog entry for a code, matching {ENTITY} families as well
+func LookupCode(code string) (CodeInfo, bool) {
+	return catalog.lookup(code)
+}
+
+// IsRegisteredCode reports whether code is declared in the catalog
+func IsRegisteredCode(code string) bool {
+	_, ok := catalog.lookup(code)
+	return ok
+}
+
+// EntityPattern turns a code family into a regular expression,
+// e.g. {ENTITY}_NOT_FOUND -> ^[A-Z][A-Z0-9]*(?:_[A-Z0-9]+)*_NOT_FOUND$
+func EntityPattern(code string) string {
+	return "^" + strings.ReplaceAll(regexp.QuoteMeta(code), regexp.QuoteMeta(EntityPlaceholder), entityToken) + "$"
+}
+
+// Catalog returns every registered code sorted by code
+func Catalog() []CodeInfo {
+	catalog.mu.RLock()
+	defer catalog.mu.RUnlock()
+
+	infos := make([]CodeInfo, 0, len(catalog.codes))
+	for _, info := range catalog.codes {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Code < infos[j].Code
+	})
+	return infos
+}
+
+// EntityCode fills a code family with an entity name, e.g. ({ENTITY}_NOT_FOUND, "order item") -> ORDER_ITEM_NOT_FOUND
+func EntityCode(pattern string, entity string) string {
+	entity = MakeUpperCaseWithUnderscores(strings.ReplaceAll(strings.TrimSpace(entity), "_", " "))
+	return strings.ReplaceAll(pattern, EntityPlaceholder, entity)
+}
+
+// CodeForStatus returns the generic code for an HTTP status, e.g. 404 -> NOT_FOUND
+func CodeForStatus(status int) string {
+	return MakeUpperCaseWithUnderscores(http.StatusText(status))
+}
@@ -0,0 +1,54 @@
+package errs
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntityFamiliesMatchWellFormedEntities(t *testing.T) {
+	for _, code := range []string{"RECORD_ALREADY_EXISTS", "RESOURCE_NOT_FOUND", "USER_ALREADY_EXISTS", "ORDER_ITEM2_REQUIRED"} {
+		assert.True(t, IsRegisteredCode(code), code)
+	}
+	for _, code := range []string{"_NOT_FOUND", "ORDER__ITEM_NOT_FOUND", "2FA_REQUIRED", "user_NOT_FOUND", "USER_NOT_FOUND_X"} {
+		assert.False(t, IsRegisteredCode(code), code)
+	}
+
+	info, ok := LookupCode(EntityCode(CodeEntityNotFound, "widget_part"))
+	assert.True(t, ok)
+	assert.Equal(t, CodeEntityNotFound, info.Code)
+	assert.Equal(t, http.StatusNotFound, info.Status)
+}
+
+func TestCatalogStatusesMatchConstructors(t *testing.T) {
+	for _, err := range []*HTTPError{
+		NewUnauthorizedError("", false),
+		NewForbiddenError("", false),
+		NewBadRequestError("", false, nil, nil, nil),
+		NewNotFoundError("", false, nil),
+		NewConflictError("", false, nil),
+		NewPreconditionFailedError("", false, nil),
+		NewUnprocessableEntityError("", false, nil, nil),
+		NewRequestTooLargeError("", false, 1),
+		NewNotAcceptableError("", false, nil),
+		NewUnsupportedMediaTypeError("", false, nil),
+		NewTooManyRequestsError("", false, 0),
+		NewServiceUnavailableError("", false, nil, 0),
+		NewInternalServerError(),
+	} {
+		info, ok := LookupCode(err.Code)
+		if assert.True(t, ok, "%s is not registered", err.Code) {
+			assert.Equal(t, err.Status, info.Status, err.Code)
+		}
+	}
+}
+
+func TestEntityPattern(t *testing.T) {
+	re := regexp.MustCompile(EntityPattern(CodeEntityInvalid))
+	assert.True(t, re.MatchString("GADGET_INVALID"))
+	assert.True(t, re.MatchString("ORDER_ITEM_INVALID"))
+	assert.False(t, re.MatchString("GADGET_INVALID_X"))
+	assert.False(t, re.MatchString("_INVALID"))
+}
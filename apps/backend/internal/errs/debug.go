@@ -0,0 +1,74 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// RedactedMessage replaces internal messages of unexpected failures in production
+const RedactedMessage = "An unexpected error occurred"
+
+// DebugInfo exposes the internals of an error to trusted callers only, see NewDebugInfo
+type DebugInfo struct {
+	Chain []string `json:"chain"`           //messages of the wrapped errors, outermost first
+	Stack string   `json:"stack,omitempty"` //stack trace of the innermost error that recorded one
+}
+
+type stackTracer interface {
+	StackTrace() pkgerrors.StackTrace
+}
+
+// NewDebugInfo walks the wrapped error chain, nil for a nil error
+func NewDebugInfo(err error) *DebugInfo {
+	if err == nil {
+		return nil
+	}
+
+	info := &DebugInfo{}
+	for current := err; current != nil; current = unwrapOnce(current) {
+		info.Chain = append(info.Chain, fmt.Sprintf("%T: %s", current, current.Error()))
+
+		// keep the deepest stack, that's where the failure started
+		if tracer, ok := current.(stackTracer); ok {
+			info.Stack = strings.TrimSpace(fmt.Sprintf("%+v", tracer.StackTrace()))
+		}
+	}
+	return info
+}
+
+// unwrapOnce follows both the standard Unwrap and the pkg/errors Cause convention
+func unwrapOnce(err error) error {
+	if next := errors.Unwrap(err); next != nil {
+		return next
+	}
+	if causer, ok := err.(interface{ Cause() error }); ok {
+		if next := causer.Cause(); next != err {
+			return next
+		}
+	}
+	return nil
+}
+
+// Redact strips everything but the status and code from an unexpected failure.
+// Errors with Override set or below 500 were written for clients and are returned unchanged.
+func (e *HTTPError) Redact(requestID string) *HTTPError {
+	if e.Override || e.Status < http.StatusInternalServerError {
+		return e
+	}
+
+	cp := e.clone()
+	cp.Message = RedactedMessage
+	cp.Errors = nil
+	cp.Details = nil
+	cp.Debug = nil
+	cp.MessageKey = ""
+	cp.MessageParams = nil
+	if requestID != "" {
+		cp.Details = map[string]any{"request_id": requestID}
+	}
+	return cp
+}
@@ -1,23 +1,42 @@
 package errs
 
 import(
+	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 type FieldError struct{
-	Field string `json:"field"`
-	Error string `json:"error"`
+	Field 		string `json:"field"` //path as the client sent it e.g. items[2].address.zip
+	Error 		string `json:"error"`
+	Location	string `json:"location,omitempty"` //where the field was sent: body, query or path
+	Key			string `json:"-"` //i18n message key for Error, see internal/i18n
+	Params		map[string]string `json:"-"` //placeholders for Key
 }
 
+const(
+	FieldLocationBody 	= "body"
+	FieldLocationQuery 	= "query"
+	FieldLocationPath 	= "path"
+)
+
 type ActionType string
 
 const(
-	ActionTypeRedirect ActionType = "redirect"
+	ActionTypeRedirect 			ActionType = "redirect"
+	ActionTypeReauthenticate 	ActionType = "reauthenticate"
+	ActionTypeRetry 			ActionType = "retry"
+	ActionTypeUpgradePlan 		ActionType = "upgrade_plan"
+	ActionTypeRefresh 			ActionType = "refresh"
+	ActionTypeContactSupport 	ActionType = "contact_support"
 )
 //eg: if user session expired send action(redirect to another route)
+//build with the New*Action helpers in actions.go so the payload matches the type
 type Action struct{
 	Type 	ActionType	`json:"type"`
 	Message	string		`json:"message"`
-	Value 	string		`json:"value"`
+	Value 	string		`json:"value"` //primary value as text (url, seconds, plan...) for simple clients
+	Payload	any			`json:"payload,omitempty"` //typed payload, see *Payload structs
 }
 
 type HTTPError struct{
@@ -27,26 +46,130 @@ type HTTPError struct{
 	Override bool   		`json:"override"` //override the current  message/code/status 
 	Errors	 []FieldError 	`json:"errors"` //field level error 
 	Action  *Action 		`json:"actions"`//ation to be taken
+	Details	 map[string]any	`json:"details,omitempty"` //structured metadata e.g. the identifier that wasn't found
+	MessageKey	  string			`json:"-"` //i18n key for Message, without one the code is used as key
+	MessageParams map[string]string	`json:"-"` //placeholders for MessageKey e.g. {entity}
+	Debug	 *DebugInfo		`json:"debug,omitempty"` //wrapped error chain and stack, only filled in for trusted debug callers
+	Cause	 error			`json:"-"` //underlying error, logged but never sent to clients
+	Headers	 http.Header	`json:"-"` //response headers e.g. Retry-After, applied by the global error handler
 }
 
 func (e *HTTPError) Error() string{
 	return e.Message
 }
 
+func (e *HTTPError) Unwrap() error{
+	return e.Cause
+}
+
 func (e *HTTPError) Is(target error) bool{
 	_, ok := target.(*HTTPError)
 	return ok
 }
 
+//With* methods return a copy so shared errors can be customised per request
+
+func (e *HTTPError) clone() *HTTPError{
+	cp := *e
+	if e.Details != nil {
+		cp.Details = make(map[string]any, len(e.Details))
+		for k, v := range e.Details {
+			cp.Details[k] = v
+		}
+	}
+	if e.Headers != nil {
+		cp.Headers = e.Headers.Clone()
+	}
+	if e.MessageParams != nil {
+		cp.MessageParams = make(map[string]string, len(e.MessageParams))
+		for k, v := range e.MessageParams {
+			cp.MessageParams[k] = v
+		}
+	}
+	return &cp
+}
+
 func (e *HTTPError) WithMessage(message string) *HTTPError{
-	return &HTTPError{
-		Code: e.Code,
-		Message: message,
-		Status: e.Status,
-		Override: e.Override,
-		Errors: e.Errors,
-		Action: e.Action,
+	cp := e.clone()
+	cp.Message = message
+	return cp
+}
+
+//key and params let the global error handler translate Message, see internal/i18n
+func (e *HTTPError) WithMessageKey(key string, params map[string]string) *HTTPError{
+	cp := e.clone()
+	cp.MessageKey = key
+	cp.MessageParams = params
+	return cp
+}
+
+func (e *HTTPError) WithCode(code string) *HTTPError{
+	cp := e.clone()
+	cp.Code = code
+	return cp
+}
+
+func (e *HTTPError) WithErrors(errors ...FieldError) *HTTPError{
+	cp := e.clone()
+	cp.Errors = append(cp.Errors[:len(cp.Errors):len(cp.Errors)], errors...)
+	return cp
+}
+
+//panics on an invalid action, it's a programming error like a bad regexp
+func (e *HTTPError) WithAction(action *Action) *HTTPError{
+	if action != nil {
+		if err := action.Validate(); err != nil {
+			panic(err)
+		}
 	}
+	cp := e.clone()
+	cp.Action = action
+	return cp
+}
+
+func (e *HTTPError) WithDetail(key string, value any) *HTTPError{
+	cp := e.clone()
+	if cp.Details == nil {
+		cp.Details = map[string]any{}
+	}
+	cp.Details[key] = value
+	return cp
+}
+
+func (e *HTTPError) WithDetails(details map[string]any) *HTTPError{
+	cp := e.clone()
+	if cp.Details == nil {
+		cp.Details = make(map[string]any, len(details))
+	}
+	for k, v := range details {
+		cp.Details[k] = v
+	}
+	return cp
+}
+
+func (e *HTTPError) WithCause(cause error) *HTTPError{
+	cp := e.clone()
+	cp.Cause = cause
+	return cp
+}
+
+func (e *HTTPError) WithHeader(key string, value string) *HTTPError{
+	cp := e.clone()
+	if cp.Headers == nil {
+		cp.Headers = http.Header{}
+	}
+	cp.Headers.Set(key, value)
+	return cp
+}
+
+//Retry-After in whole seconds, rounded up so clients never retry too early
+func (e *HTTPError) WithRetryAfter(after time.Duration) *HTTPError{
+	return e.WithHeader("Retry-After", strconv.FormatInt(retryAfterSeconds(after), 10))
+}
+
+//Retry-After header plus a retry action for clients that only read the body
+func (e *HTTPError) WithRetry(after time.Duration) *HTTPError{
+	return e.WithRetryAfter(after).WithAction(NewRetryAction("Please try again", after))
 }
 
 func MakeUpperCaseWithUnderscores(str string) string{
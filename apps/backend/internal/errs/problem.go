@@ -0,0 +1,52 @@
+package errs
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	// MIMEApplicationProblemJSON is the RFC 7807 media type for error documents
+	MIMEApplicationProblemJSON = "application/problem+json"
+
+	// ProblemTypeBase prefixes the problem type URI, the error code is appended in kebab case
+	ProblemTypeBase = "/errors/"
+)
+
+// ProblemDetails is an RFC 7807 problem document.
+// code, errors, actions and details are extension members carrying the same data as HTTPError.
+type ProblemDetails struct {
+	Type     string         `json:"type"`
+	Title    string         `json:"title"`
+	Status   int            `json:"status"`
+	Detail   string         `json:"detail,omitempty"`
+	Instance string         `json:"instance,omitempty"`
+	Code     string         `json:"code"`
+	Override bool           `json:"override"`
+	Errors   []FieldError   `json:"errors,omitempty"`
+	Action   *Action        `json:"actions,omitempty"`
+	Details  map[string]any `json:"details,omitempty"`
+	Debug    *DebugInfo     `json:"debug,omitempty"`
+}
+
+// ProblemType returns the type URI for an error code, e.g. USER_NOT_FOUND -> /errors/user-not-found
+func ProblemType(code string) string {
+	return ProblemTypeBase + strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+}
+
+// ToProblem converts the error into a problem document, instance identifies this occurrence (the request id)
+func (e *HTTPError) ToProblem(instance string) ProblemDetails {
+	return ProblemDetails{
+		Type:     ProblemType(e.Code),
+		Title:    http.StatusText(e.Status),
+		Status:   e.Status,
+		Detail:   e.Message,
+		Instance: instance,
+		Code:     e.Code,
+		Override: e.Override,
+		Errors:   e.Errors,
+		Action:   e.Action,
+		Details:  e.Details,
+		Debug:    e.Debug,
+	}
+}
@@ -2,20 +2,22 @@ package errs
 
 import (
 	"net/http"
+	"time"
 )
 
 func NewUnauthorizedError(message string, override bool) *HTTPError{
 	return &HTTPError{
-		Code:     MakeUpperCaseWithUnderscores(http.StatusText(http.StatusUnauthorized)),
+		Code:     CodeUnauthorized,
 		Message:  message,
 		Status:   http.StatusUnauthorized,
 		Override: override,
+		Action:   NewReauthenticateAction("Please sign in again", "unauthenticated"),
 	}
 }
 
 func NewForbiddenError(message string, override bool) *HTTPError {
 	return &HTTPError{
-		Code:     MakeUpperCaseWithUnderscores(http.StatusText(http.StatusForbidden)),
+		Code:     CodeForbidden,
 		Message:  message,
 		Status:   http.StatusForbidden,
 		Override: override,
@@ -23,24 +25,28 @@ func NewForbiddenError(message string, override bool) *HTTPError {
 }
 
 func NewBadRequestError(message string, override bool, code *string, errors []FieldError, action *Action) *HTTPError {
-	formattedCode := MakeUpperCaseWithUnderscores(http.StatusText(http.StatusBadRequest))
+	formattedCode := CodeBadRequest
 
 	if code != nil {
 		formattedCode = *code
 	}
 
-	return &HTTPError{
+	err := &HTTPError{
 		Code:     formattedCode,
 		Message:  message,
 		Status:   http.StatusBadRequest,
 		Override: override,
 		Errors:   errors,
-		Action:   action,
 	}
+
+	if action != nil {
+		return err.WithAction(action)
+	}
+	return err
 }
 
 func NewNotFoundError(message string, override bool, code *string) *HTTPError {
-	formattedCode := MakeUpperCaseWithUnderscores(http.StatusText(http.StatusNotFound))
+	formattedCode := CodeNotFound
 
 	if code != nil {
 		formattedCode = *code
@@ -54,9 +60,124 @@ func NewNotFoundError(message string, override bool, code *string) *HTTPError {
 	}
 }
 
+func NewConflictError(message string, override bool, code *string) *HTTPError {
+	formattedCode := CodeConflict
+
+	if code != nil {
+		formattedCode = *code
+	}
+
+	return &HTTPError{
+		Code:     formattedCode,
+		Message:  message,
+		Status:   http.StatusConflict,
+		Override: override,
+	}
+}
+
+func NewPreconditionFailedError(message string, override bool, code *string) *HTTPError {
+	formattedCode := CodePreconditionFailed
+
+	if code != nil {
+		formattedCode = *code
+	}
+
+	return &HTTPError{
+		Code:     formattedCode,
+		Message:  message,
+		Status:   http.StatusPreconditionFailed,
+		Override: override,
+	}
+}
+
+func NewUnprocessableEntityError(message string, override bool, code *string, errors []FieldError) *HTTPError {
+	formattedCode := CodeUnprocessableEntity
+
+	if code != nil {
+		formattedCode = *code
+	}
+
+	return &HTTPError{
+		Code:     formattedCode,
+		Message:  message,
+		Status:   http.StatusUnprocessableEntity,
+		Override: override,
+		Errors:   errors,
+	}
+}
+
+// limit is reported in details so clients can tell how much they may send
+func NewRequestTooLargeError(message string, override bool, limit int64) *HTTPError {
+	return &HTTPError{
+		Code:     CodeRequestTooLarge,
+		Message:  message,
+		Status:   http.StatusRequestEntityTooLarge,
+		Override: override,
+		Details:  map[string]any{"limit_bytes": limit},
+	}
+}
+
+// supported lists the formats the endpoint can produce
+func NewNotAcceptableError(message string, override bool, supported []string) *HTTPError {
+	return &HTTPError{
+		Code:     CodeNotAcceptable,
+		Message:  message,
+		Status:   http.StatusNotAcceptable,
+		Override: override,
+		Details:  map[string]any{"supported": supported},
+	}
+}
+
+// allowed lists the media types the endpoint accepts
+func NewUnsupportedMediaTypeError(message string, override bool, allowed []string) *HTTPError {
+	return &HTTPError{
+		Code:     CodeUnsupportedMediaType,
+		Message:  message,
+		Status:   http.StatusUnsupportedMediaType,
+		Override: override,
+		Details:  map[string]any{"allowed": allowed},
+	}
+}
+
+// retryAfter of 0 leaves the Retry-After header out
+func NewTooManyRequestsError(message string, override bool, retryAfter time.Duration) *HTTPError {
+	err := &HTTPError{
+		Code:     CodeTooManyRequests,
+		Message:  message,
+		Status:   http.StatusTooManyRequests,
+		Override: override,
+	}
+
+	if retryAfter > 0 {
+		return err.WithRetry(retryAfter)
+	}
+	return err
+}
+
+// retryAfter of 0 leaves the Retry-After header out
+func NewServiceUnavailableError(message string, override bool, code *string, retryAfter time.Duration) *HTTPError {
+	formattedCode := CodeServiceUnavailable
+
+	if code != nil {
+		formattedCode = *code
+	}
+
+	err := &HTTPError{
+		Code:     formattedCode,
+		Message:  message,
+		Status:   http.StatusServiceUnavailable,
+		Override: override,
+	}
+
+	if retryAfter > 0 {
+		return err.WithRetry(retryAfter)
+	}
+	return err
+}
+
 func NewInternalServerError() *HTTPError {
 	return &HTTPError{
-		Code:     MakeUpperCaseWithUnderscores(http.StatusText(http.StatusInternalServerError)),
+		Code:     CodeInternalServerError,
 		Message:  http.StatusText(http.StatusInternalServerError),
 		Status:   http.StatusInternalServerError,
 		Override: false,
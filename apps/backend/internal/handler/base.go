@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"reflect"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -22,10 +23,10 @@ func NewHandler(s *server.Server) Handler {
 }
 
 // HandlerFunc represents a typed handler function that processes a request and returns a response
-type HandlerFunc[Req validation.Validatable, Res any] func(c echo.Context, req Req) (Res, error)
+type HandlerFunc[Req any, Res any] func(c echo.Context, req Req) (Res, error)
 
 // HandlerFuncNoContent represents a typed handler function that processes a request without returning content
-type HandlerFuncNoContent[Req validation.Validatable] func(c echo.Context, req Req) error
+type HandlerFuncNoContent[Req any] func(c echo.Context, req Req) error
 
 // ResponseHandler defines the interface for handling different response types
 type ResponseHandler interface {
@@ -37,9 +38,13 @@ type ResponseHandler interface {
 // JSONResponseHandler handles JSON responses
 type JSONResponseHandler struct {
 	status int
+	cache  *CachePolicy // ETag and Cache-Control, nil sends neither
 }
 
 func (h JSONResponseHandler) Handle(c echo.Context, result interface{}) error {
+	if h.cache != nil {
+		return h.cache.respond(c, h.status, result)
+	}
 	return c.JSON(h.status, result)
 }
 
@@ -77,7 +82,7 @@ type FileResponseHandler struct {
 
 func (h FileResponseHandler) Handle(c echo.Context, result interface{}) error {
 	data := result.([]byte)
-	c.Response().Header().Set("Content-Disposition", "attachment; filename="+h.filename)
+	c.Response().Header().Set(echo.HeaderContentDisposition, contentDisposition("attachment", h.filename))
 	return c.Blob(h.status, h.contentType, data)
 }
 
@@ -96,14 +101,15 @@ func (h FileResponseHandler) AddAttributes(txn *newrelic.Transaction, result int
 	}
 }
 
-// handleRequest is the unified handler function that eliminates code duplication
-func handleRequest[Req validation.Validatable](
+// handleRequest is the unified handler function that eliminates code duplication.
+// Every request binds into its own Req so concurrent requests never share state.
+func handleRequest[Req any](
 	c echo.Context,
-	req Req,
 	handler func(c echo.Context, req Req) (interface{}, error),
 	responseHandler ResponseHandler,
 ) error {
 	start := time.Now()
+	req, target := newRequest[Req]()
 	method := c.Request().Method
 	path := c.Path()
 	route := path
@@ -130,6 +136,18 @@ func handleRequest[Req validation.Validatable](
 			Str("content_type", fileHandler.contentType)
 	}
 	
+	// negotiated handlers pick the format up front so unsupported ones fail before any work is done
+	if negotiated, ok := responseHandler.(NegotiatedResponseHandler); ok {
+		format, err := negotiated.negotiate(c)
+		if err != nil {
+			return err
+		}
+		loggerBuilder = loggerBuilder.Str("format", format)
+		if txn != nil {
+			txn.AddAttribute("response.format", format)
+		}
+	}
+
 	logger := loggerBuilder.Logger()
 
 	// user.id is already set by tracing middleware
@@ -138,7 +156,7 @@ func handleRequest[Req validation.Validatable](
 
 	// Validation with observability
 	validationStart := time.Now()
-	if err := validation.BindAndValidate(c, req); err != nil {
+	if err := validation.BindAndValidate(c, target); err != nil {
 		validationDuration := time.Since(validationStart)
 
 		logger.Error().
@@ -166,7 +184,7 @@ func handleRequest[Req validation.Validatable](
 
 	// Execute handler with observability
 	handlerStart := time.Now()
-	result, err := handler(c, req)
+	result, err := handler(c, *req)
 	handlerDuration := time.Since(handlerStart)
 
 	if err != nil {
@@ -207,29 +225,27 @@ func handleRequest[Req validation.Validatable](
 }
 
 // Handle wraps a handler with validation, error handling, logging, metrics, and tracing
-func Handle[Req validation.Validatable, Res any](
+func Handle[Req any, Res any](
 	h Handler,
 	handler HandlerFunc[Req, Res],
 	status int,
-	req Req,
 ) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		return handleRequest(c, req, func(c echo.Context, req Req) (interface{}, error) {
+		return handleRequest(c, func(c echo.Context, req Req) (interface{}, error) {
 			return handler(c, req)
 		}, JSONResponseHandler{status: status})
 	}
 }
 
-func HandleFile[Req validation.Validatable](
+func HandleFile[Req any](
 	h Handler,
 	handler HandlerFunc[Req, []byte],
 	status int,
-	req Req,
 	filename string,
 	contentType string,
 ) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		return handleRequest(c, req, func(c echo.Context, req Req) (interface{}, error) {
+		return handleRequest(c, func(c echo.Context, req Req) (interface{}, error) {
 			return handler(c, req)
 		}, FileResponseHandler{
 			status:      status,
@@ -240,16 +256,26 @@ func HandleFile[Req validation.Validatable](
 }
 
 // HandleNoContent wraps a handler with validation, error handling, logging, metrics, and tracing for endpoints that don't return content
-func HandleNoContent[Req validation.Validatable](
+func HandleNoContent[Req any](
 	h Handler,
 	handler HandlerFuncNoContent[Req],
 	status int,
-	req Req,
 ) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		return handleRequest(c, req, func(c echo.Context, req Req) (interface{}, error) {
+		return handleRequest(c, func(c echo.Context, req Req) (interface{}, error) {
 			err := handler(c, req)
 			return nil, err
 		}, NoContentResponseHandler{status: status})
 	}
-}
\ No newline at end of file
+}
+
+// newRequest allocates a zero Req and returns it with the value to bind into. Pointer request types
+// point at a fresh struct, value types are bound through req itself.
+func newRequest[Req any]() (*Req, any) {
+	req := new(Req)
+	if t := reflect.TypeOf(req).Elem(); t.Kind() == reflect.Pointer {
+		reflect.ValueOf(req).Elem().Set(reflect.New(t.Elem()))
+		return req, *req
+	}
+	return req, req
+}
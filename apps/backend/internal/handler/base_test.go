@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type echoRequest struct {
+	ID   string   `param:"id"`
+	Name string   `json:"name" validate:"required"`
+	Tags []string `json:"tags"`
+}
+
+type echoResponse struct {
+	ID   string   `json:"id"`
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// run with -race: every request must bind into its own Req even when Req is a pointer type
+func TestHandleBindsEachRequestSeparately(t *testing.T) {
+	e := echo.New()
+	e.POST("/items/:id", Handle(Handler{}, func(c echo.Context, req *echoRequest) (echoResponse, error) {
+		// give concurrent requests a chance to overwrite a shared Req
+		time.Sleep(time.Millisecond)
+		req.Tags = append(req.Tags, "seen")
+		return echoResponse{ID: req.ID, Name: req.Name, Tags: req.Tags}, nil
+	}, http.StatusOK))
+
+	const requests = 64
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			body := fmt.Sprintf(`{"name":"name-%d","tags":["tag-%d"]}`, i, i)
+			req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/items/%d", i), strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if !assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String()) {
+				return
+			}
+			var response echoResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, echoResponse{
+				ID:   fmt.Sprint(i),
+				Name: fmt.Sprintf("name-%d", i),
+				Tags: []string{fmt.Sprintf("tag-%d", i), "seen"},
+			}, response)
+		}(i)
+	}
+	wg.Wait()
+}
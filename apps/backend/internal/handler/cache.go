@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Mayank85Y/boil/internal/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/newrelic/go-agent/v3/newrelic"
+)
+
+const (
+	HeaderETag        = "ETag"
+	HeaderIfNoneMatch = "If-None-Match"
+)
+
+// ETagMode selects how JSON responses are validated
+type ETagMode int
+
+const (
+	// ETagNone sends no ETag
+	ETagNone ETagMode = iota
+	// ETagStrong hashes the serialized body, it is exact but the body is always built
+	ETagStrong
+	// ETagWeak uses the Version of a Versioner result (see model.Base and model.PaginatedResponse)
+	// and falls back to ETagStrong when there is none
+	ETagWeak
+)
+
+func (m ETagMode) String() string {
+	switch m {
+	case ETagStrong:
+		return "strong"
+	case ETagWeak:
+		return "weak"
+	default:
+		return "none"
+	}
+}
+
+// Versioner is implemented by results that know when they changed, e.g. from updated_at or a version column
+type Versioner interface {
+	Version() string
+}
+
+// CachePolicy makes GET responses of a route cacheable, clients revalidate with If-None-Match and get
+// 304 Not Modified while the ETag still matches
+type CachePolicy struct {
+	ETag ETagMode
+	// CacheControl is sent as is e.g. "private, max-age=0, must-revalidate", empty leaves the header out
+	CacheControl string
+}
+
+func (p CachePolicy) String() string {
+	s := "etag " + p.ETag.String()
+	if p.CacheControl != "" {
+		s += "; " + p.CacheControl
+	}
+	return s
+}
+
+// respond writes result as JSON, answering If-None-Match with 304 when the ETag matches
+func (p CachePolicy) respond(c echo.Context, status int, result interface{}) error {
+	header := c.Response().Header()
+	if p.CacheControl != "" {
+		header.Set(echo.HeaderCacheControl, p.CacheControl)
+	}
+
+	method := c.Request().Method
+	conditional := p.ETag != ETagNone && (method == http.MethodGet || method == http.MethodHead) &&
+		status >= 200 && status < 300
+	if !conditional {
+		return c.JSON(status, result)
+	}
+
+	etag := ""
+	if p.ETag == ETagWeak {
+		if versioned, ok := result.(Versioner); ok && versioned.Version() != "" {
+			etag = weakETag(versioned.Version())
+		}
+	}
+
+	// the weak path skips serializing when the client already has the current version
+	var body []byte
+	if etag == "" {
+		var err error
+		if body, err = encodeJSON(result); err != nil {
+			return err
+		}
+		etag = strongETag(body)
+	}
+	header.Set(HeaderETag, etag)
+
+	hit := etagMatches(c.Request().Header.Get(HeaderIfNoneMatch), etag)
+	recordCacheResult(c, hit)
+	if hit {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	if body == nil {
+		var err error
+		if body, err = encodeJSON(result); err != nil {
+			return err
+		}
+	}
+	return c.Blob(status, echo.MIMEApplicationJSON, body)
+}
+
+// encodeJSON produces the same bytes as c.JSON so ETags match what is sent
+func encodeJSON(result interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(result); err != nil {
+		return nil, fmt.Errorf("failed to encode response: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func strongETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// weakETag hashes the version so any string is safe to use, ETags only allow a subset of ASCII
+func weakETag(version string) string {
+	sum := sha256.Sum256([]byte(version))
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches compares If-None-Match weakly as RFC 9110 requires for GET
+func etagMatches(ifNoneMatch string, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+	opaque := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == opaque {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheCounters track revalidations per route since the process started
+type cacheCounters struct {
+	hits  atomic.Int64
+	total atomic.Int64
+}
+
+var cacheStats sync.Map // route -> *cacheCounters
+
+func recordCacheResult(c echo.Context, hit bool) {
+	route := c.Path()
+	value, _ := cacheStats.LoadOrStore(route, &cacheCounters{})
+	counters := value.(*cacheCounters)
+
+	total := counters.total.Add(1)
+	var hits int64
+	if hit {
+		hits = counters.hits.Add(1)
+	} else {
+		hits = counters.hits.Load()
+	}
+	ratio := float64(hits) / float64(total)
+
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	middleware.GetLogger(c).Debug().
+		Str("route", route).
+		Str("cache", result).
+		Int64("cache_hits", hits).
+		Int64("cache_requests", total).
+		Float64("cache_hit_ratio", ratio).
+		Msg("conditional request")
+
+	if txn := newrelic.FromContext(c.Request().Context()); txn != nil {
+		txn.AddAttribute("cache.result", result)
+		txn.AddAttribute("cache.hit_ratio", ratio)
+	}
+}
+
+// HandleCached wraps a handler like Handle and makes its GET responses revalidatable, see CachePolicy
+func HandleCached[Req any, Res any](
+	h Handler,
+	handler HandlerFunc[Req, Res],
+	status int,
+	policy CachePolicy,
+) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return handleRequest(c, func(c echo.Context, req Req) (interface{}, error) {
+			return handler(c, req)
+		}, JSONResponseHandler{status: status, cache: &policy})
+	}
+}
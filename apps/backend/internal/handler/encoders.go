@@ -0,0 +1,322 @@
+package handler
+
+import (
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const (
+	FormatJSON    = "json"
+	FormatCSV     = "csv"
+	FormatMsgPack = "msgpack"
+
+	MIMETextCSV            = "text/csv"
+	MIMEApplicationMsgPack = "application/vnd.msgpack"
+)
+
+func init() {
+	RegisterEncoder(FormatJSON, JSONEncoder{})
+	RegisterEncoder(FormatCSV, CSVEncoder{})
+	RegisterEncoder(FormatMsgPack, MsgPackEncoder{})
+}
+
+// JSONEncoder is the default format
+type JSONEncoder struct{}
+
+func (JSONEncoder) ContentType() string {
+	return "application/json"
+}
+
+func (JSONEncoder) Encode(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// MsgPackEncoder writes MessagePack using the json tags, so keys match the JSON representation
+type MsgPackEncoder struct{}
+
+func (MsgPackEncoder) ContentType() string {
+	return MIMEApplicationMsgPack
+}
+
+func (MsgPackEncoder) Encode(w io.Writer, v any) error {
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+	return enc.Encode(v)
+}
+
+// CSVEncoder writes one row per element. Paginated responses (a struct with a "data" slice, see
+// model.PaginatedResponse) are unwrapped to their rows, nested structs become dotted columns e.g. owner.name
+// and slices or maps inside a row are written as JSON.
+type CSVEncoder struct{}
+
+func (CSVEncoder) ContentType() string {
+	return MIMETextCSV + "; charset=utf-8"
+}
+
+func (CSVEncoder) Encode(w io.Writer, v any) error {
+	rows := csvRows(reflect.ValueOf(v))
+
+	var header []string
+	var cells func(row reflect.Value) ([]string, error)
+
+	elem := indirectType(rows.Type().Elem())
+	switch {
+	case elem.Kind() == reflect.Struct && !isTextValue(elem):
+		columns, err := csvColumns(elem, "", nil, map[reflect.Type]bool{})
+		if err != nil {
+			return err
+		}
+		for _, column := range columns {
+			header = append(header, column.name)
+		}
+		cells = func(row reflect.Value) ([]string, error) {
+			record := make([]string, len(columns))
+			for i, column := range columns {
+				value, ok := fieldByIndex(row, column.index)
+				if !ok {
+					continue
+				}
+				cell, err := csvCell(value)
+				if err != nil {
+					return nil, fmt.Errorf("column %s: %w", column.name, err)
+				}
+				record[i] = cell
+			}
+			return record, nil
+		}
+	case elem.Kind() == reflect.Map && elem.Key().Kind() == reflect.String:
+		header = mapColumns(rows)
+		cells = func(row reflect.Value) ([]string, error) {
+			row = indirect(row)
+			record := make([]string, len(header))
+			for i, key := range header {
+				if !row.IsValid() {
+					break
+				}
+				value := row.MapIndex(reflect.ValueOf(key).Convert(row.Type().Key()))
+				if !value.IsValid() {
+					continue
+				}
+				cell, err := csvCell(value)
+				if err != nil {
+					return nil, fmt.Errorf("column %s: %w", key, err)
+				}
+				record[i] = cell
+			}
+			return record, nil
+		}
+	default:
+		header = []string{"value"}
+		cells = func(row reflect.Value) ([]string, error) {
+			cell, err := csvCell(row)
+			return []string{cell}, err
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for i := 0; i < rows.Len(); i++ {
+		record, err := cells(rows.Index(i))
+		if err != nil {
+			return fmt.Errorf("row %d: %w", i, err)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvRows returns the slice to write, single values become a one row slice
+func csvRows(v reflect.Value) reflect.Value {
+	v = indirect(v)
+	if !v.IsValid() {
+		return reflect.ValueOf([]any{})
+	}
+
+	if v.Kind() == reflect.Struct {
+		if field, ok := v.Type().FieldByName("Data"); ok && jsonFieldName(field) == "data" {
+			if data := v.FieldByIndex(field.Index); data.Kind() == reflect.Slice {
+				return data
+			}
+		}
+	}
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return v
+		}
+	}
+
+	rows := reflect.MakeSlice(reflect.SliceOf(v.Type()), 1, 1)
+	rows.Index(0).Set(v)
+	return rows
+}
+
+type csvColumn struct {
+	name  string
+	index []int
+}
+
+// csvColumns follows encoding/json naming, embedded structs are flattened like json does.
+// expanding holds the struct types on the current path, a type containing itself has no fixed set of
+// columns and is rejected.
+func csvColumns(t reflect.Type, prefix string, index []int, expanding map[reflect.Type]bool) ([]csvColumn, error) {
+	if expanding[t] {
+		return nil, fmt.Errorf("csv: %s refers to itself at %s, it can't be flattened into columns",
+			t, strings.TrimSuffix(prefix, "."))
+	}
+	expanding[t] = true
+	defer delete(expanding, t)
+
+	var columns []csvColumn
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := jsonFieldName(field)
+		if name == "-" {
+			continue
+		}
+
+		fieldIndex := append(append([]int(nil), index...), i)
+		fieldType := indirectType(field.Type)
+
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			embedded, err := csvColumns(fieldType, prefix, fieldIndex, expanding)
+			if err != nil {
+				return nil, err
+			}
+			columns = append(columns, embedded...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if fieldType.Kind() == reflect.Struct && !isTextValue(fieldType) {
+			nested, err := csvColumns(fieldType, prefix+name+".", fieldIndex, expanding)
+			if err != nil {
+				return nil, err
+			}
+			columns = append(columns, nested...)
+			continue
+		}
+		columns = append(columns, csvColumn{name: prefix + name, index: fieldIndex})
+	}
+	return columns, nil
+}
+
+// mapColumns is the sorted union of the keys of all rows
+func mapColumns(rows reflect.Value) []string {
+	seen := map[string]bool{}
+	for i := 0; i < rows.Len(); i++ {
+		row := indirect(rows.Index(i))
+		if !row.IsValid() {
+			continue
+		}
+		for _, key := range row.MapKeys() {
+			seen[key.String()] = true
+		}
+	}
+
+	columns := make([]string, 0, len(seen))
+	for key := range seen {
+		columns = append(columns, key)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex that reports nil pointers on the way instead of panicking
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for _, i := range index {
+		v = indirect(v)
+		if !v.IsValid() {
+			return reflect.Value{}, false
+		}
+		v = v.Field(i)
+	}
+	return v, true
+}
+
+func csvCell(v reflect.Value) (string, error) {
+	v = indirect(v)
+	if !v.IsValid() {
+		return "", nil
+	}
+
+	// times, ids and decimals, text from users only ever comes in as plain strings
+	if marshaler, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+	if v.CanAddr() {
+		if marshaler, ok := v.Addr().Interface().(encoding.TextMarshaler); ok {
+			text, err := marshaler.MarshalText()
+			return string(text), err
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return escapeFormula(v.String()), nil
+	case reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return "", nil
+		}
+		encoded, err := json.Marshal(v.Interface())
+		return string(encoded), err
+	case reflect.Array, reflect.Struct:
+		encoded, err := json.Marshal(v.Interface())
+		return string(encoded), err
+	default:
+		return fmt.Sprint(v.Interface()), nil
+	}
+}
+
+// escapeFormula keeps spreadsheets from evaluating user supplied text as a formula
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func isTextValue(t reflect.Type) bool {
+	return t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	return name
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/labstack/echo/v4"
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"golang.org/x/text/unicode/norm"
+)
+
+// File is returned by streaming file handlers. Range requests, If-Modified-Since, If-Range and HEAD
+// are answered by http.ServeContent, Content is closed afterwards when it is an io.Closer.
+type File struct {
+	Content     io.ReadSeeker
+	Name        string    // download name, sent as RFC 6266 filename* when it isn't plain ASCII
+	ContentType string    // detected from Name or the first bytes when empty
+	Size        int64     // spares the seek to the end when known, 0 means unknown
+	ModTime     time.Time // Last-Modified and If-Modified-Since, zero leaves both out
+	Inline      bool      // display in the browser instead of downloading
+}
+
+// HandlerFuncFile returns the file to stream for the request
+type HandlerFuncFile[Req any] func(c echo.Context, req Req) (*File, error)
+
+// StreamFileResponseHandler streams a *File without buffering it
+type StreamFileResponseHandler struct{}
+
+func (h StreamFileResponseHandler) Handle(c echo.Context, result interface{}) error {
+	file, ok := result.(*File)
+	if !ok || file == nil || file.Content == nil {
+		return c.NoContent(http.StatusNoContent)
+	}
+	if closer, ok := file.Content.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	header := c.Response().Header()
+	disposition := "attachment"
+	if file.Inline {
+		disposition = "inline"
+	}
+	header.Set(echo.HeaderContentDisposition, contentDisposition(disposition, file.Name))
+	if file.ContentType != "" {
+		header.Set(echo.HeaderContentType, file.ContentType)
+	}
+
+	var content io.ReadSeeker = file.Content
+	if file.Size > 0 {
+		content = sizedContent{ReadSeeker: file.Content, size: file.Size}
+	}
+	http.ServeContent(c.Response(), c.Request(), file.Name, file.ModTime, content)
+	return nil
+}
+
+func (h StreamFileResponseHandler) GetOperation() string {
+	return "handler_file_stream"
+}
+
+func (h StreamFileResponseHandler) AddAttributes(txn *newrelic.Transaction, result interface{}) {
+	if txn == nil {
+		return
+	}
+	if file, ok := result.(*File); ok && file != nil {
+		txn.AddAttribute("file.name", file.Name)
+		txn.AddAttribute("file.content_type", file.ContentType)
+		if file.Size > 0 {
+			txn.AddAttribute("file.size_bytes", file.Size)
+		}
+	}
+}
+
+// HandleFileStream wraps a handler returning a *File with validation, error handling, logging, metrics, and tracing
+func HandleFileStream[Req any](
+	h Handler,
+	handler HandlerFuncFile[Req],
+) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return handleRequest(c, func(c echo.Context, req Req) (interface{}, error) {
+			return handler(c, req)
+		}, StreamFileResponseHandler{})
+	}
+}
+
+// sizedContent answers the size lookup of http.ServeContent from File.Size so storage
+// backends don't have to seek to the end
+type sizedContent struct {
+	io.ReadSeeker
+	size int64
+}
+
+func (s sizedContent) Seek(offset int64, whence int) (int64, error) {
+	if whence == io.SeekEnd && offset == 0 {
+		return s.size, nil
+	}
+	return s.ReadSeeker.Seek(offset, whence)
+}
+
+// contentDisposition builds an RFC 6266 header. Non-ASCII names get an ASCII filename fallback
+// for old clients and the exact name as UTF-8 in filename*.
+func contentDisposition(disposition string, filename string) string {
+	if filename == "" {
+		return disposition
+	}
+
+	fallback := asciiFilename(filename)
+	value := disposition + `; filename="` + fallback + `"`
+	if fallback != filename {
+		value += "; filename*=UTF-8''" + extValueEscape(filename)
+	}
+	return value
+}
+
+// asciiFilename drops accents (é -> e) and replaces what's left outside printable ASCII,
+// quotes and backslashes can't appear in a quoted-string either
+func asciiFilename(filename string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(filename) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r == '"' || r == '\\' || r < 0x20 || r > 0x7e:
+			b.WriteByte('_')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// extValueEscape percent-encodes everything but the RFC 8187 attr-char set
+func extValueEscape(s string) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		if c < 0x80 && (unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)) || strings.IndexByte("!#$&+-.^_`|~", c) >= 0) {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
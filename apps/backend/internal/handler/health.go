@@ -11,6 +11,25 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+// HealthResponse documents the body written by CheckHealth
+type HealthResponse struct {
+	Status      string       `json:"status" validate:"oneof=healthy unhealthy"`
+	Timestamp   time.Time    `json:"timestamp"`
+	Environment string       `json:"environment"`
+	Checks      HealthChecks `json:"checks"`
+}
+
+type HealthChecks struct {
+	Database HealthCheck  `json:"database"`
+	Redis    *HealthCheck `json:"redis,omitempty"`
+}
+
+type HealthCheck struct {
+	Status       string `json:"status"`
+	ResponseTime string `json:"response_time"`
+	Error        string `json:"error,omitempty"`
+}
+
 type HealthHandler struct {
 	Handler
 }
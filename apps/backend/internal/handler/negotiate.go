@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/labstack/echo/v4"
+	"github.com/newrelic/go-agent/v3/newrelic"
+)
+
+const (
+	// FormatQueryParam overrides the Accept header, e.g. ?format=csv for links opened in a browser
+	FormatQueryParam = "format"
+
+	// ResponseFormatKey holds the negotiated format for the rest of the request
+	ResponseFormatKey = "response_format"
+)
+
+// Encoder writes a response body in one format
+type Encoder interface {
+	ContentType() string
+	Encode(w io.Writer, v any) error
+}
+
+var encoders = struct {
+	sync.RWMutex
+	byFormat map[string]Encoder
+	order    []string // registration order breaks ties between equally acceptable formats
+}{byFormat: map[string]Encoder{}}
+
+// RegisterEncoder makes a format available to negotiating handlers, it panics on duplicates
+func RegisterEncoder(format string, encoder Encoder) {
+	encoders.Lock()
+	defer encoders.Unlock()
+
+	if _, exists := encoders.byFormat[format]; exists {
+		panic(fmt.Sprintf("handler: encoder for format %q registered twice", format))
+	}
+	encoders.byFormat[format] = encoder
+	encoders.order = append(encoders.order, format)
+}
+
+// Formats lists the registered formats, JSON first
+func Formats() []string {
+	encoders.RLock()
+	defer encoders.RUnlock()
+	return append([]string(nil), encoders.order...)
+}
+
+func encoderFor(format string) (Encoder, bool) {
+	encoders.RLock()
+	defer encoders.RUnlock()
+	encoder, ok := encoders.byFormat[format]
+	return encoder, ok
+}
+
+// NegotiatedResponseHandler encodes the result in the format the client asked for
+type NegotiatedResponseHandler struct {
+	status  int
+	formats []string // allowed formats, all registered ones when empty
+}
+
+func (h NegotiatedResponseHandler) Handle(c echo.Context, result interface{}) error {
+	format, ok := c.Get(ResponseFormatKey).(string)
+	if !ok {
+		var err error
+		if format, err = h.negotiate(c); err != nil {
+			return err
+		}
+	}
+	encoder, _ := encoderFor(format)
+
+	// encode first so a failure still gets a proper error response
+	var body bytes.Buffer
+	if err := encoder.Encode(&body, result); err != nil {
+		return fmt.Errorf("failed to encode %s response: %w", format, err)
+	}
+	return c.Blob(h.status, encoder.ContentType(), body.Bytes())
+}
+
+func (h NegotiatedResponseHandler) GetOperation() string {
+	return "handler_negotiated"
+}
+
+func (h NegotiatedResponseHandler) AddAttributes(txn *newrelic.Transaction, result interface{}) {
+	// response.format is set once negotiated, see handleRequest
+}
+
+// negotiate picks the format from ?format= or the Accept header and remembers it on the context
+func (h NegotiatedResponseHandler) negotiate(c echo.Context) (string, error) {
+	c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+
+	formats := h.formats
+	if len(formats) == 0 {
+		formats = Formats()
+	}
+
+	var format string
+	if requested := c.QueryParam(FormatQueryParam); requested != "" {
+		for _, candidate := range formats {
+			if strings.EqualFold(candidate, requested) {
+				format = candidate
+				break
+			}
+		}
+	} else {
+		format = bestFormat(c.Request().Header.Get(echo.HeaderAccept), formats)
+	}
+
+	if format == "" {
+		return "", errs.NewNotAcceptableError(
+			"None of the requested formats are available, supported: "+strings.Join(formats, ", "), true, formats)
+	}
+	c.Set(ResponseFormatKey, format)
+	return format, nil
+}
+
+type mediaRange struct {
+	mediaType string
+	q         float64
+}
+
+// bestFormat returns the format with the highest q value, earlier formats win ties
+// and an empty Accept header means the first one
+func bestFormat(accept string, formats []string) string {
+	if strings.TrimSpace(accept) == "" {
+		return formats[0]
+	}
+
+	ranges := parseAccept(accept)
+	best, bestQ := "", 0.0
+	for _, format := range formats {
+		encoder, ok := encoderFor(format)
+		if !ok {
+			continue
+		}
+		contentType, _, _ := mime.ParseMediaType(encoder.ContentType())
+		if q := acceptQuality(ranges, contentType); q > bestQ {
+			best, bestQ = format, q
+		}
+	}
+	return best
+}
+
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		r := mediaRange{mediaType: strings.ToLower(strings.TrimSpace(params[0])), q: 1}
+		for _, param := range params[1:] {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.EqualFold(name, "q") {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					r.q = parsed
+				}
+			}
+		}
+		ranges = append(ranges, r)
+	}
+
+	// the most specific range decides, e.g. text/csv;q=0 beats */*
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return strings.Count(ranges[i].mediaType, "*") < strings.Count(ranges[j].mediaType, "*")
+	})
+	return ranges
+}
+
+func acceptQuality(ranges []mediaRange, contentType string) float64 {
+	mainType, _, _ := strings.Cut(contentType, "/")
+	for _, r := range ranges {
+		if r.mediaType == contentType || r.mediaType == mainType+"/*" || r.mediaType == "*/*" {
+			return r.q
+		}
+	}
+	return 0
+}
+
+// HandleNegotiated wraps a handler with validation, error handling, logging, metrics, and tracing and encodes
+// the response as JSON, CSV, MessagePack or any registered format the client asks for. formats restricts
+// the choice, JSON comes first and is used when the client doesn't care.
+func HandleNegotiated[Req any, Res any](
+	h Handler,
+	handler HandlerFunc[Req, Res],
+	status int,
+	formats ...string,
+) echo.HandlerFunc {
+	for _, format := range formats {
+		if _, ok := encoderFor(format); !ok {
+			panic(fmt.Sprintf("handler: no encoder registered for format %q", format))
+		}
+	}
+
+	responseHandler := NegotiatedResponseHandler{status: status, formats: formats}
+	return func(c echo.Context) error {
+		return handleRequest(c, func(c echo.Context, req Req) (interface{}, error) {
+			return handler(c, req)
+		}, responseHandler)
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type negotiatedOwner struct {
+	Name string `json:"name" msgpack:"name"`
+}
+
+type negotiatedRow struct {
+	ID    int             `json:"id" msgpack:"id"`
+	Title string          `json:"title" msgpack:"title"`
+	Owner negotiatedOwner `json:"owner" msgpack:"owner"`
+	Tags  []string        `json:"tags" msgpack:"tags"`
+}
+
+var negotiatedRows = []negotiatedRow{
+	{ID: 1, Title: "first", Owner: negotiatedOwner{Name: "ada"}, Tags: []string{"a"}},
+	{ID: 2, Title: "=SUM(A1)", Owner: negotiatedOwner{Name: "grace"}},
+}
+
+func serveNegotiated(t *testing.T, target string, accept string, formats ...string) (*httptest.ResponseRecorder, error) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if accept != "" {
+		req.Header.Set(echo.HeaderAccept, accept)
+	}
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	err := HandleNegotiated(Handler{}, func(c echo.Context, req struct{}) ([]negotiatedRow, error) {
+		return negotiatedRows, nil
+	}, http.StatusOK, formats...)(c)
+	return rec, err
+}
+
+func TestNegotiatedCSV(t *testing.T) {
+	rec, err := serveNegotiated(t, "/rows", "text/csv, application/json;q=0.5")
+	require.NoError(t, err)
+
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Header().Values(echo.HeaderVary), echo.HeaderAccept)
+	assert.Equal(t, "id,title,owner.name,tags\n1,first,ada,\"[\"\"a\"\"]\"\n2,'=SUM(A1),grace,\n", rec.Body.String())
+}
+
+func TestNegotiatedMsgPack(t *testing.T) {
+	rec, err := serveNegotiated(t, "/rows", "application/vnd.msgpack")
+	require.NoError(t, err)
+	assert.Equal(t, MIMEApplicationMsgPack, rec.Header().Get(echo.HeaderContentType))
+
+	var decoded []negotiatedRow
+	require.NoError(t, msgpack.Unmarshal(rec.Body.Bytes(), &decoded))
+	assert.Equal(t, negotiatedRows, decoded)
+}
+
+func TestNegotiatedDefaultsAndOverrides(t *testing.T) {
+	rec, err := serveNegotiated(t, "/rows", "")
+	require.NoError(t, err)
+	assert.Equal(t, echo.MIMEApplicationJSON, rec.Header().Get(echo.HeaderContentType))
+
+	rec, err = serveNegotiated(t, "/rows?format=CSV", "application/json")
+	require.NoError(t, err)
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+
+	_, err = serveNegotiated(t, "/rows", "text/csv", FormatJSON)
+	var httpErr *errs.HTTPError
+	require.True(t, errors.As(err, &httpErr), "got %v", err)
+	assert.Equal(t, http.StatusNotAcceptable, httpErr.Status)
+}
+
+type csvNode struct {
+	Name   string   `json:"name"`
+	Parent *csvNode `json:"parent"`
+}
+
+type csvWrapper struct {
+	Node csvNode `json:"node"`
+}
+
+type csvTwice struct {
+	Author negotiatedOwner `json:"author"`
+	Editor negotiatedOwner `json:"editor"`
+}
+
+func TestCSVRejectsSelfReferentialTypes(t *testing.T) {
+	for name, rows := range map[string]any{
+		"direct":  []csvNode{{Name: "root"}},
+		"nested":  []csvWrapper{{}},
+		"pointer": &csvNode{Name: "leaf", Parent: &csvNode{Name: "root"}},
+	} {
+		err := CSVEncoder{}.Encode(&bytes.Buffer{}, rows)
+		assert.ErrorContains(t, err, "refers to itself", name)
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, CSVEncoder{}.Encode(&out, []csvTwice{{Author: negotiatedOwner{"a"}, Editor: negotiatedOwner{"b"}}}),
+		"the same type in two fields is not a cycle")
+	assert.Equal(t, "author.name,editor.name\na,b\n", out.String())
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/Mayank85Y/boil/internal/openapi"
 	"github.com/Mayank85Y/boil/internal/server"
 	"github.com/labstack/echo/v4"
 )
@@ -34,4 +35,17 @@ func (h *OpenAPIHandler) ServeOpenAPIUI(c echo.Context) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// ServeSpec serves the document generated from the registered routes
+func (h *OpenAPIHandler) ServeSpec(spec *openapi.Registry) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		body, err := spec.JSON()
+		if err != nil {
+			return fmt.Errorf("failed to render OpenAPI document: %w", err)
+		}
+
+		c.Response().Header().Set("Cache-Control", "no-cache")
+		return c.JSONBlob(http.StatusOK, body)
+	}
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/Mayank85Y/boil/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderLink       = "Link"
+	HeaderTotalCount = "X-Total-Count"
+)
+
+// PageOptions tunes the limits of one list endpoint
+type PageOptions struct {
+	// DefaultLimit applies when the client sends no limit, 0 means model.DefaultPageLimit
+	DefaultLimit int
+	// MaxLimit rejects larger limits with 400, 0 means model.MaxPageLimit which is also the upper bound
+	MaxLimit int
+}
+
+func (o PageOptions) defaultLimit() int {
+	if o.DefaultLimit <= 0 {
+		return min(model.DefaultPageLimit, o.maxLimit())
+	}
+	return min(o.DefaultLimit, o.maxLimit())
+}
+
+func (o PageOptions) maxLimit() int {
+	if o.MaxLimit <= 0 || o.MaxLimit > model.MaxPageLimit {
+		return model.MaxPageLimit
+	}
+	return o.MaxLimit
+}
+
+// Paginated is implemented by request types embedding model.PageQuery
+type Paginated interface {
+	Pagination() model.PageQuery
+}
+
+// HandlerFuncPaginated returns one page of rows and the total row count, page has the defaults applied
+type HandlerFuncPaginated[Req any, T any] func(c echo.Context, req Req, page model.PageQuery) ([]T, int, error)
+
+// HandlePaginated wraps a list handler with validation, error handling, logging, metrics, and tracing.
+// It binds page and limit through the model.PageQuery embedded in Req, enforces opts.MaxLimit and answers
+// with a model.PaginatedResponse plus RFC 8288 Link (first, prev, next, last) and X-Total-Count headers.
+func HandlePaginated[Req Paginated, T any](
+	h Handler,
+	opts PageOptions,
+	handler HandlerFuncPaginated[Req, T],
+) echo.HandlerFunc {
+	return handlePaginated(h, opts, handler, nil)
+}
+
+func handlePaginated[Req Paginated, T any](
+	h Handler,
+	opts PageOptions,
+	handler HandlerFuncPaginated[Req, T],
+	cache *CachePolicy,
+) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return handleRequest(c, func(c echo.Context, req Req) (interface{}, error) {
+			page, err := pageQuery(req.Pagination(), opts)
+			if err != nil {
+				return nil, err
+			}
+
+			rows, total, err := handler(c, req, page)
+			if err != nil {
+				return nil, err
+			}
+
+			response := model.NewPaginatedResponse(rows, page, total)
+			setPaginationHeaders(c, response)
+			return response, nil
+		}, JSONResponseHandler{status: http.StatusOK, cache: cache})
+	}
+}
+
+// pageQuery applies the route limits, model.PageQuery tags already rejected values below 1.
+// The upper bound only lives here so the error names the limit of the route rather than model.MaxPageLimit.
+func pageQuery(query model.PageQuery, opts PageOptions) (model.PageQuery, error) {
+	if limit := opts.maxLimit(); query.Limit > limit {
+		param := strconv.Itoa(limit)
+		return query, errs.NewBadRequestError("Validation failed", true, nil, []errs.FieldError{{
+			Field:    "limit",
+			Error:    "must not exceed " + param,
+			Location: errs.FieldLocationQuery,
+			Key:      "validation.max.number",
+			Params:   map[string]string{"param": param},
+		}}, nil).WithMessageKey("validation.failed", nil)
+	}
+	return query.WithDefaults(opts.defaultLimit()), nil
+}
+
+// setPaginationHeaders links to the neighbouring pages with the other query parameters kept as they are,
+// the links are relative references resolved against the request URL
+func setPaginationHeaders[T any](c echo.Context, response model.PaginatedResponse[T]) {
+	header := c.Response().Header()
+	header.Set(HeaderTotalCount, strconv.Itoa(response.Total))
+
+	last := max(response.TotalPages, 1)
+	links := []string{pageLink(c.Request().URL, 1, response.Limit, "first")}
+	if response.Page > 1 {
+		links = append(links, pageLink(c.Request().URL, min(response.Page-1, last), response.Limit, "prev"))
+	}
+	if response.Page < response.TotalPages {
+		links = append(links, pageLink(c.Request().URL, response.Page+1, response.Limit, "next"))
+	}
+	links = append(links, pageLink(c.Request().URL, last, response.Limit, "last"))
+	header.Set(HeaderLink, strings.Join(links, ", "))
+}
+
+func pageLink(current *url.URL, page int, limit int, rel string) string {
+	query := current.Query()
+	query.Set("page", strconv.Itoa(page))
+	query.Set("limit", strconv.Itoa(limit))
+
+	target := url.URL{Path: current.Path, RawPath: current.RawPath, RawQuery: query.Encode()}
+	return fmt.Sprintf(`<%s>; rel="%s"`, target.String(), rel)
+}
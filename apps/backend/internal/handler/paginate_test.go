@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/Mayank85Y/boil/internal/model"
+	"github.com/Mayank85Y/boil/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageQueryLimits(t *testing.T) {
+	tests := []struct {
+		name  string
+		query model.PageQuery
+		opts  PageOptions
+		want  model.PageQuery
+		max   string // non empty when the limit is rejected
+	}{
+		{"defaults", model.PageQuery{}, PageOptions{}, model.PageQuery{Page: 1, Limit: model.DefaultPageLimit}, ""},
+		{"route default", model.PageQuery{Page: 3}, PageOptions{DefaultLimit: 5}, model.PageQuery{Page: 3, Limit: 5}, ""},
+		{"at max", model.PageQuery{Limit: model.MaxPageLimit}, PageOptions{}, model.PageQuery{Page: 1, Limit: model.MaxPageLimit}, ""},
+		{"above max", model.PageQuery{Limit: model.MaxPageLimit + 1}, PageOptions{}, model.PageQuery{}, "100"},
+		{"above route max", model.PageQuery{Limit: 11}, PageOptions{MaxLimit: 10}, model.PageQuery{}, "10"},
+		{"route max above model max", model.PageQuery{Limit: 500}, PageOptions{MaxLimit: 1000}, model.PageQuery{}, "100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pageQuery(tt.query, tt.opts)
+			if tt.max == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+				return
+			}
+
+			httpErr, ok := err.(*errs.HTTPError)
+			require.True(t, ok, err)
+			assert.Equal(t, http.StatusBadRequest, httpErr.Status)
+			assert.Equal(t, "validation.failed", httpErr.MessageKey)
+			require.Len(t, httpErr.Errors, 1)
+			assert.Equal(t, "limit", httpErr.Errors[0].Field)
+			assert.Equal(t, errs.FieldLocationQuery, httpErr.Errors[0].Location)
+			assert.Equal(t, map[string]string{"param": tt.max}, httpErr.Errors[0].Params)
+		})
+	}
+}
+
+// a tag max would reject large limits with model.MaxPageLimit before the route limit is even looked at
+func TestPageQueryTagsLeaveMaxToRoute(t *testing.T) {
+	query := model.PageQuery{Limit: model.MaxPageLimit + 50}
+	require.NoError(t, validation.Validate(context.Background(), &query))
+
+	_, err := pageQuery(query, PageOptions{MaxLimit: 10})
+	httpErr, ok := err.(*errs.HTTPError)
+	require.True(t, ok, err)
+	require.Len(t, httpErr.Errors, 1)
+	assert.Equal(t, map[string]string{"param": "10"}, httpErr.Errors[0].Params)
+}
@@ -0,0 +1,470 @@
+package handler
+
+import (
+	"mime"
+	"net/http"
+	"path"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Mayank85Y/boil/internal/lib/storage"
+	"github.com/Mayank85Y/boil/internal/middleware"
+	"github.com/Mayank85Y/boil/internal/model"
+	"github.com/Mayank85Y/boil/internal/openapi"
+	"github.com/labstack/echo/v4"
+)
+
+// RouteSpec documents a route and declares the middlewares it needs, Routes applies them in the order
+// timeout, auth, permissions, rate limit, body limit, idempotency
+type RouteSpec struct {
+	OperationID string
+	Summary     string
+	Description string
+	Tags        []string
+	// Auth puts the route behind the auth middleware and marks it as requiring a bearer token
+	Auth bool
+	// Permissions are all required, they imply Auth
+	Permissions []string
+	// RateLimit is enforced per user (per IP when unauthenticated) on top of middleware.DefaultRateLimit
+	RateLimit *middleware.RateLimitPolicy
+	// Timeout cancels the request context, 0 means no deadline
+	Timeout time.Duration
+	// MaxBodyBytes replaces validation.DefaultMaxBodyBytes, 0 keeps the default
+	MaxBodyBytes int64
+	// StrictBody rejects JSON bodies with fields the request type doesn't have, see validation.BindOptions
+	StrictBody bool
+	// Idempotency replays the stored response to retries sending the same Idempotency-Key header
+	Idempotency *middleware.IdempotencyPolicy
+	// Cache adds an ETag and Cache-Control to responses of Typed and TypedPaginated routes and answers
+	// If-None-Match with 304
+	Cache *CachePolicy
+	// Hidden routes are served and listed but left out of the OpenAPI document
+	Hidden bool
+	// Response documents untyped routes built with Untyped
+	Response any
+}
+
+// RequiresAuth reports whether the route is behind the auth middleware
+func (s RouteSpec) RequiresAuth() bool {
+	return s.Auth || len(s.Permissions) > 0
+}
+
+// Route is one entry of a route table, build it with Typed, TypedPaginated, TypedNegotiated, TypedNoContent,
+// TypedFile, TypedFileStream, TypedSSE, TypedUpload or Untyped and mount it with Routes.Mount
+type Route struct {
+	Method string
+	Path   string // full path once mounted
+	Status int
+	Spec   RouteSpec
+
+	handler echo.HandlerFunc
+	op      openapi.Operation
+}
+
+// Typed is a route served by Handle, or HandleCached when spec.Cache is set
+func Typed[Req any, Res any](
+	method string,
+	routePath string,
+	h Handler,
+	handler HandlerFunc[Req, Res],
+	status int,
+	spec RouteSpec,
+) Route {
+	serve := Handle(h, handler, status)
+	if spec.Cache != nil {
+		serve = HandleCached(h, handler, status, *spec.Cache)
+	}
+	return Route{
+		Method:  method,
+		Path:    routePath,
+		Status:  status,
+		Spec:    spec,
+		handler: serve,
+		op: openapi.Operation{
+			Request:  typeOf[Req](),
+			Response: typeOf[Res](),
+		},
+	}
+}
+
+// TypedPaginated is a GET route served by HandlePaginated, spec.Cache applies like it does for Typed
+func TypedPaginated[Req Paginated, T any](
+	routePath string,
+	h Handler,
+	opts PageOptions,
+	handler HandlerFuncPaginated[Req, T],
+	spec RouteSpec,
+) Route {
+	return Route{
+		Method:  http.MethodGet,
+		Path:    routePath,
+		Status:  http.StatusOK,
+		Spec:    spec,
+		handler: handlePaginated(h, opts, handler, spec.Cache),
+		op: openapi.Operation{
+			Request:   typeOf[Req](),
+			Response:  typeOf[model.PaginatedResponse[T]](),
+			Paginated: true,
+		},
+	}
+}
+
+// TypedNegotiated is a route served by HandleNegotiated
+func TypedNegotiated[Req any, Res any](
+	method string,
+	routePath string,
+	h Handler,
+	handler HandlerFunc[Req, Res],
+	status int,
+	spec RouteSpec,
+	formats ...string,
+) Route {
+	// panics on unknown formats before they are looked up below
+	served := HandleNegotiated(h, handler, status, formats...)
+	if len(formats) == 0 {
+		formats = Formats()
+	}
+
+	var alternatives []string
+	for _, format := range formats[1:] {
+		encoder, _ := encoderFor(format)
+		contentType, _, _ := mime.ParseMediaType(encoder.ContentType())
+		alternatives = append(alternatives, contentType)
+	}
+	first, _ := encoderFor(formats[0])
+	contentType, _, _ := mime.ParseMediaType(first.ContentType())
+
+	return Route{
+		Method:  method,
+		Path:    routePath,
+		Status:  status,
+		Spec:    spec,
+		handler: served,
+		op: openapi.Operation{
+			Request:         typeOf[Req](),
+			Response:        typeOf[Res](),
+			ContentType:     contentType,
+			AltContentTypes: alternatives,
+		},
+	}
+}
+
+// TypedNoContent is a route served by HandleNoContent
+func TypedNoContent[Req any](
+	method string,
+	routePath string,
+	h Handler,
+	handler HandlerFuncNoContent[Req],
+	status int,
+	spec RouteSpec,
+) Route {
+	return Route{
+		Method:  method,
+		Path:    routePath,
+		Status:  status,
+		Spec:    spec,
+		handler: HandleNoContent(h, handler, status),
+		op: openapi.Operation{
+			Request: typeOf[Req](),
+		},
+	}
+}
+
+// TypedFile is a route served by HandleFile
+func TypedFile[Req any](
+	method string,
+	routePath string,
+	h Handler,
+	handler HandlerFunc[Req, []byte],
+	status int,
+	filename string,
+	contentType string,
+	spec RouteSpec,
+) Route {
+	return Route{
+		Method:  method,
+		Path:    routePath,
+		Status:  status,
+		Spec:    spec,
+		handler: HandleFile(h, handler, status, filename, contentType),
+		op: openapi.Operation{
+			Request:     typeOf[Req](),
+			Response:    typeOf[[]byte](),
+			ContentType: contentType,
+		},
+	}
+}
+
+// TypedFileStream is a route served by HandleFileStream, contentType documents what it usually returns
+func TypedFileStream[Req any](
+	method string,
+	routePath string,
+	h Handler,
+	handler HandlerFuncFile[Req],
+	contentType string,
+	spec RouteSpec,
+) Route {
+	return Route{
+		Method:  method,
+		Path:    routePath,
+		Status:  http.StatusOK,
+		Spec:    spec,
+		handler: HandleFileStream(h, handler),
+		op: openapi.Operation{
+			Request:     typeOf[Req](),
+			Response:    typeOf[[]byte](),
+			ContentType: contentType,
+		},
+	}
+}
+
+// TypedSSE is a route served by HandleSSE
+func TypedSSE[Req any](
+	method string,
+	routePath string,
+	h Handler,
+	opts SSEOptions,
+	handler HandlerFuncSSE[Req],
+	spec RouteSpec,
+) Route {
+	return Route{
+		Method:  method,
+		Path:    routePath,
+		Status:  http.StatusOK,
+		Spec:    spec,
+		handler: HandleSSE(h, opts, handler),
+		op: openapi.Operation{
+			Request:     typeOf[Req](),
+			Response:    typeOf[string](),
+			ContentType: MIMETextEventStream,
+		},
+	}
+}
+
+// TypedUpload is a route served by HandleUpload, set spec.MaxBodyBytes when files may exceed the default body limit
+func TypedUpload[Req any, Res any](
+	method string,
+	routePath string,
+	h Handler,
+	store storage.Storage,
+	opts UploadOptions,
+	handler HandlerFuncUpload[Req, Res],
+	status int,
+	spec RouteSpec,
+) Route {
+	field := opts.Field
+	if field == "" {
+		field = "file"
+	}
+	return Route{
+		Method:  method,
+		Path:    routePath,
+		Status:  status,
+		Spec:    spec,
+		handler: HandleUpload(h, store, opts, handler, status),
+		op: openapi.Operation{
+			Request:   typeOf[Req](),
+			Response:  typeOf[Res](),
+			FileField: field,
+			MaxFiles:  opts.maxFiles(),
+		},
+	}
+}
+
+// Untyped is a route served by a plain echo handler, spec.Response documents its body
+func Untyped(method string, routePath string, h echo.HandlerFunc, status int, spec RouteSpec) Route {
+	var response reflect.Type
+	if spec.Response != nil {
+		response = reflect.TypeOf(spec.Response)
+	}
+	return Route{
+		Method:  method,
+		Path:    routePath,
+		Status:  status,
+		Spec:    spec,
+		handler: h,
+		op: openapi.Operation{
+			Response: response,
+		},
+	}
+}
+
+// routeTable is shared by a Routes and all of its groups
+type routeTable struct {
+	mu     sync.RWMutex
+	routes []Route
+}
+
+// Routes mounts route tables on echo, applies the middlewares each route declares
+// and records the routes in the OpenAPI registry
+type Routes struct {
+	echo        *echo.Echo
+	prefix      string
+	middlewares []echo.MiddlewareFunc
+	spec        *openapi.Registry
+	mws         *middleware.Middlewares
+	table       *routeTable
+}
+
+// NewRoutes wraps e. mws builds the per route middlewares, nil skips them when the routes are only
+// collected e.g. to generate the spec.
+func NewRoutes(e *echo.Echo, spec *openapi.Registry, mws *middleware.Middlewares) *Routes {
+	return &Routes{
+		echo:  e,
+		spec:  spec,
+		mws:   mws,
+		table: &routeTable{},
+	}
+}
+
+// Group returns routes below prefix sharing the given middlewares
+func (r *Routes) Group(prefix string, middlewares ...echo.MiddlewareFunc) *Routes {
+	return &Routes{
+		echo:        r.echo,
+		prefix:      path.Join(r.prefix, prefix),
+		middlewares: append(append([]echo.MiddlewareFunc(nil), r.middlewares...), middlewares...),
+		spec:        r.spec,
+		mws:         r.mws,
+		table:       r.table,
+	}
+}
+
+func (r *Routes) Spec() *openapi.Registry {
+	return r.spec
+}
+
+// Table returns every mounted route sorted by path and method
+func (r *Routes) Table() []Route {
+	r.table.mu.RLock()
+	defer r.table.mu.RUnlock()
+
+	routes := append([]Route(nil), r.table.routes...)
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// Mount registers the routes below the prefix of r
+func (r *Routes) Mount(routes ...Route) {
+	for _, route := range routes {
+		r.mount(route)
+	}
+}
+
+func (r *Routes) mount(route Route) {
+	route.Path = path.Join("/", r.prefix, route.Path)
+
+	r.echo.Add(route.Method, route.Path, route.handler, r.routeMiddlewares(route.Spec)...)
+
+	r.table.mu.Lock()
+	r.table.routes = append(r.table.routes, route)
+	r.table.mu.Unlock()
+
+	if r.spec == nil || route.Spec.Hidden {
+		return
+	}
+	op := route.op
+	op.Method = route.Method
+	op.Path = route.Path
+	op.Status = route.Status
+	op.OperationID = route.Spec.OperationID
+	op.Summary = route.Spec.Summary
+	op.Description = route.Spec.Description
+	op.Tags = route.Spec.Tags
+	op.Auth = route.Spec.RequiresAuth()
+	op.Permissions = route.Spec.Permissions
+	if route.Spec.Cache != nil && route.Spec.Cache.ETag != ETagNone {
+		op.Conditional = true
+	}
+	if route.Spec.Idempotency != nil {
+		op.Idempotent = true
+		op.IdempotencyKeyRequired = route.Spec.Idempotency.Required
+	}
+	r.spec.Add(op)
+}
+
+func (r *Routes) routeMiddlewares(spec RouteSpec) []echo.MiddlewareFunc {
+	middlewares := append([]echo.MiddlewareFunc(nil), r.middlewares...)
+	if r.mws == nil {
+		return middlewares
+	}
+
+	if spec.Timeout > 0 {
+		middlewares = append(middlewares, middleware.Timeout(spec.Timeout))
+	}
+	if spec.RequiresAuth() {
+		middlewares = append(middlewares, r.mws.Auth.RequireAuth)
+	}
+	if len(spec.Permissions) > 0 {
+		middlewares = append(middlewares, r.mws.Auth.RequirePermissions(spec.Permissions...))
+	}
+	if spec.RateLimit != nil {
+		middlewares = append(middlewares, r.mws.RateLimit.Limit(*spec.RateLimit))
+	}
+	if spec.MaxBodyBytes > 0 {
+		middlewares = append(middlewares, middleware.BodyLimit(spec.MaxBodyBytes))
+	}
+	if spec.StrictBody {
+		middlewares = append(middlewares, middleware.StrictBind)
+	}
+	if spec.Idempotency != nil {
+		middlewares = append(middlewares, r.mws.Idempotency.Idempotent(*spec.Idempotency))
+	}
+	return middlewares
+}
+
+// Register mounts a single route served by Handle
+func Register[Req any, Res any](
+	r *Routes,
+	method string,
+	routePath string,
+	h Handler,
+	handler HandlerFunc[Req, Res],
+	status int,
+	spec RouteSpec,
+) {
+	r.Mount(Typed(method, routePath, h, handler, status, spec))
+}
+
+// RegisterNoContent mounts a single route served by HandleNoContent
+func RegisterNoContent[Req any](
+	r *Routes,
+	method string,
+	routePath string,
+	h Handler,
+	handler HandlerFuncNoContent[Req],
+	status int,
+	spec RouteSpec,
+) {
+	r.Mount(TypedNoContent(method, routePath, h, handler, status, spec))
+}
+
+// RegisterFile mounts a single route served by HandleFile
+func RegisterFile[Req any](
+	r *Routes,
+	method string,
+	routePath string,
+	h Handler,
+	handler HandlerFunc[Req, []byte],
+	status int,
+	filename string,
+	contentType string,
+	spec RouteSpec,
+) {
+	r.Mount(TypedFile(method, routePath, h, handler, status, filename, contentType, spec))
+}
+
+// Raw mounts a single plain echo handler
+func (r *Routes) Raw(method string, routePath string, h echo.HandlerFunc, status int, spec RouteSpec) {
+	r.Mount(Untyped(method, routePath, h, status, spec))
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Mayank85Y/boil/internal/config"
+	"github.com/Mayank85Y/boil/internal/middleware"
+	"github.com/Mayank85Y/boil/internal/server"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteSpecStrictBody(t *testing.T) {
+	logger := zerolog.Nop()
+	s := &server.Server{Config: &config.Config{}, Logger: &logger}
+
+	e := echo.New()
+	e.HTTPErrorHandler = middleware.NewGlobalMiddlewares(s).GlobalErrorHandler
+	echoName := func(c echo.Context, req *echoRequest) (echoResponse, error) {
+		return echoResponse{Name: req.Name}, nil
+	}
+	routes := NewRoutes(e, nil, &middleware.Middlewares{})
+	routes.Mount(
+		Typed(http.MethodPost, "/lenient", Handler{}, echoName, http.StatusOK, RouteSpec{}),
+		Typed(http.MethodPost, "/strict", Handler{}, echoName, http.StatusOK, RouteSpec{StrictBody: true}),
+	)
+
+	post := func(path string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	const unknown = `{"name":"ada","nmae":"typo"}`
+	assert.Equal(t, http.StatusOK, post("/lenient", unknown).Code)
+	assert.Equal(t, http.StatusOK, post("/strict", `{"name":"ada"}`).Code)
+
+	rec := post("/strict", unknown)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"nmae"`)
+
+	rec = post("/strict", `{"name":"ada"} {"name":"grace"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "must contain a single JSON value")
+}
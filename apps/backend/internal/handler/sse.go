@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/labstack/echo/v4"
+	"github.com/newrelic/go-agent/v3/newrelic"
+)
+
+const (
+	MIMETextEventStream = "text/event-stream"
+
+	// HeaderLastEventID is sent by browsers when an EventSource reconnects
+	HeaderLastEventID = "Last-Event-ID"
+
+	// DefaultSSEKeepAlive keeps proxies from closing idle streams
+	DefaultSSEKeepAlive = 15 * time.Second
+)
+
+// SSEOptions tunes an event stream
+type SSEOptions struct {
+	// KeepAlive is how long a stream may stay silent before a comment is sent, 0 means DefaultSSEKeepAlive.
+	// Keep it below the idle timeout of every proxy in front of the server.
+	KeepAlive time.Duration
+}
+
+func (o SSEOptions) keepAlive() time.Duration {
+	if o.KeepAlive <= 0 {
+		return DefaultSSEKeepAlive
+	}
+	return o.KeepAlive
+}
+
+// Event is one Server-Sent Event. Strings and []byte are sent as they are, anything else as JSON.
+type Event struct {
+	ID    string
+	Name  string // the "event" field, browsers dispatch it as "message" when empty
+	Data  any
+	Retry time.Duration // reconnection delay hint, 0 leaves it out
+}
+
+// HandlerFuncSSE streams events until it returns. Sends must also select on c.Request().Context().Done(),
+// the context is cancelled when the client disconnects and the request only ends once the handler returned.
+type HandlerFuncSSE[Req any] func(c echo.Context, req Req, events chan<- Event) error
+
+// LastEventID returns the id a reconnecting client has seen last, handlers resume after it.
+// The lastEventId query parameter covers clients that can't set headers on the first connect.
+func LastEventID(c echo.Context) string {
+	if id := c.Request().Header.Get(HeaderLastEventID); id != "" {
+		return id
+	}
+	return c.QueryParam("lastEventId")
+}
+
+// sseResult summarises a finished stream for logs and tracing
+type sseResult struct {
+	events       int
+	lastEventID  string
+	disconnected bool
+}
+
+// SSEResponseHandler handles event streams, the events are written while the handler runs
+type SSEResponseHandler struct {
+	keepAlive time.Duration
+}
+
+func (h SSEResponseHandler) Handle(c echo.Context, result interface{}) error {
+	return nil
+}
+
+func (h SSEResponseHandler) GetOperation() string {
+	return "handler_sse"
+}
+
+func (h SSEResponseHandler) AddAttributes(txn *newrelic.Transaction, result interface{}) {
+	if txn == nil {
+		return
+	}
+	if stream, ok := result.(sseResult); ok {
+		txn.AddAttribute("sse.events", stream.events)
+		txn.AddAttribute("sse.client_disconnected", stream.disconnected)
+		if stream.lastEventID != "" {
+			txn.AddAttribute("sse.last_event_id", stream.lastEventID)
+		}
+	}
+}
+
+// HandleSSE wraps a streaming handler with validation, error handling, logging, metrics, and tracing.
+// Binding and validation errors get the usual JSON error response, the stream is opened before handler
+// runs so anything it returns is sent as an error event.
+func HandleSSE[Req any](
+	h Handler,
+	opts SSEOptions,
+	handler HandlerFuncSSE[Req],
+) echo.HandlerFunc {
+	responseHandler := SSEResponseHandler{keepAlive: opts.keepAlive()}
+	return func(c echo.Context) error {
+		return handleRequest(c, func(c echo.Context, req Req) (interface{}, error) {
+			return streamEvents(c, responseHandler.keepAlive, func(events chan<- Event) error {
+				return handler(c, req, events)
+			})
+		}, responseHandler)
+	}
+}
+
+// streamEvents opens the stream, runs produce and writes what it sends until it returns or the client goes away.
+// The headers go out first so a producer that returns without sending anything still leaves a valid, empty stream.
+func streamEvents(c echo.Context, keepAlive time.Duration, produce func(events chan<- Event) error) (sseResult, error) {
+	result := sseResult{lastEventID: LastEventID(c)}
+
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	res := c.Response()
+	// streams outlive the server write timeout
+	_ = http.NewResponseController(res).SetWriteDeadline(time.Time{})
+
+	header := res.Header()
+	header.Set(echo.HeaderContentType, MIMETextEventStream)
+	header.Set(echo.HeaderCacheControl, "no-cache")
+	header.Set(echo.HeaderConnection, "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	events := make(chan Event)
+	done := make(chan error, 1)
+	go func() {
+		defer close(events)
+		done <- produce(events)
+	}()
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				err := <-done
+				if err != nil {
+					writeErrorEvent(c, err)
+				}
+				return result, err
+			}
+
+			payload, err := formatEvent(event)
+			if err != nil {
+				writeErrorEvent(c, err)
+				return stopStream(result, cancel, events, done, false), err
+			}
+			if _, err := res.Write(payload); err != nil {
+				return stopStream(result, cancel, events, done, true), nil
+			}
+			res.Flush()
+			result.events++
+
+		case <-ticker.C:
+			if _, err := fmt.Fprint(res, ": keepalive\n\n"); err != nil {
+				return stopStream(result, cancel, events, done, true), nil
+			}
+			res.Flush()
+
+		case <-ctx.Done():
+			return stopStream(result, cancel, events, done, true), nil
+		}
+	}
+}
+
+// stopStream ends the stream early. It cancels the producer and drains what it still sends until it has
+// returned, echo reuses c for other requests once the handler returns so the producer must be gone by then.
+func stopStream(result sseResult, cancel context.CancelFunc, events <-chan Event, done <-chan error, disconnected bool) sseResult {
+	cancel()
+	for range events {
+	}
+	<-done
+	result.disconnected = disconnected
+	return result
+}
+
+// formatEvent renders the event in the text/event-stream format, multi line data becomes several data fields
+func formatEvent(event Event) ([]byte, error) {
+	var b strings.Builder
+	if event.ID != "" {
+		b.WriteString("id: " + singleLine(event.ID) + "\n")
+	}
+	if event.Name != "" {
+		b.WriteString("event: " + singleLine(event.Name) + "\n")
+	}
+	if event.Retry > 0 {
+		fmt.Fprintf(&b, "retry: %d\n", event.Retry.Milliseconds())
+	}
+
+	var data string
+	switch v := event.Data.(type) {
+	case nil:
+	case string:
+		data = v
+	case []byte:
+		data = string(v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode event data: %w", err)
+		}
+		data = string(encoded)
+	}
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: " + strings.TrimSuffix(line, "\r") + "\n")
+	}
+	b.WriteString("\n")
+
+	return []byte(b.String()), nil
+}
+
+// writeErrorEvent tells the client why a running stream ended, the status line is long gone.
+// Only errors meant for clients keep their message.
+func writeErrorEvent(c echo.Context, err error) {
+	payload := map[string]string{
+		"code":    errs.CodeInternalServerError,
+		"message": errs.RedactedMessage,
+	}
+
+	var httpErr *errs.HTTPError
+	if errors.As(err, &httpErr) && (httpErr.Override || httpErr.Status < http.StatusInternalServerError) {
+		payload["code"] = httpErr.Code
+		payload["message"] = httpErr.Message
+	}
+
+	event, _ := formatEvent(Event{Name: "error", Data: payload})
+	if _, err := c.Response().Write(event); err == nil {
+		c.Response().Flush()
+	}
+}
+
+func singleLine(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// produceUntilCancelled sends events until its context is done and marks when it has returned
+func produceUntilCancelled(c echo.Context, returned *atomic.Bool) func(events chan<- Event) error {
+	return func(events chan<- Event) error {
+		defer returned.Store(true)
+		ctx := c.Request().Context()
+		for {
+			select {
+			case events <- Event{Data: "tick"}:
+			case <-ctx.Done():
+				// slow cleanup, streamEvents must still wait for it
+				time.Sleep(20 * time.Millisecond)
+				_ = c.Request().Header.Get(HeaderLastEventID)
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+func TestStreamEventsWaitsForProducerOnDisconnect(t *testing.T) {
+	ctx, disconnect := context.WithCancel(context.Background())
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/events", nil).WithContext(ctx), rec)
+
+	var returned atomic.Bool
+	time.AfterFunc(10*time.Millisecond, disconnect)
+
+	result, err := streamEvents(c, time.Hour, produceUntilCancelled(c, &returned))
+	require.NoError(t, err)
+	assert.True(t, result.disconnected)
+	assert.Positive(t, result.events)
+	assert.True(t, returned.Load(), "the producer must have returned before the handler does")
+}
+
+// failingWriter stands in for a connection that broke after the first writes
+type failingWriter struct {
+	*httptest.ResponseRecorder
+	writes int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.writes > 3 {
+		return 0, errors.New("broken pipe")
+	}
+	return w.ResponseRecorder.Write(p)
+}
+
+func TestStreamEventsWaitsForProducerOnWriteError(t *testing.T) {
+	writer := &failingWriter{ResponseRecorder: httptest.NewRecorder()}
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/events", nil), writer)
+
+	var returned atomic.Bool
+	result, err := streamEvents(c, time.Hour, produceUntilCancelled(c, &returned))
+	require.NoError(t, err)
+	assert.True(t, result.disconnected)
+	assert.True(t, returned.Load(), "the producer must have returned before the handler does")
+}
+
+func TestStreamEventsSendsEventsAndErrors(t *testing.T) {
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/events", nil), rec)
+
+	boom := errors.New("boom")
+	result, err := streamEvents(c, time.Hour, func(events chan<- Event) error {
+		events <- Event{ID: "1", Name: "greeting", Data: "hello\nworld"}
+		return boom
+	})
+	require.ErrorIs(t, err, boom)
+	assert.Equal(t, 1, result.events)
+	assert.Equal(t, MIMETextEventStream, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Body.String(), "id: 1\nevent: greeting\ndata: hello\ndata: world\n\n")
+	assert.Contains(t, rec.Body.String(), "event: error\n")
+}
+
+// clients must see an event stream even when the producer has nothing to say
+func TestStreamEventsOpensStreamBeforeProducer(t *testing.T) {
+	for name, produceErr := range map[string]error{"nil": nil, "error": errors.New("boom")} {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/events", nil), rec)
+
+			var headerAtStart string
+			result, err := streamEvents(c, time.Hour, func(events chan<- Event) error {
+				headerAtStart = rec.Header().Get(echo.HeaderContentType)
+				return produceErr
+			})
+			assert.Equal(t, produceErr, err)
+			assert.Zero(t, result.events)
+
+			assert.Equal(t, MIMETextEventStream, headerAtStart)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.True(t, rec.Flushed)
+			assert.Equal(t, "no-cache", rec.Header().Get(echo.HeaderCacheControl))
+			if produceErr == nil {
+				assert.Empty(t, rec.Body.String())
+			} else {
+				assert.Contains(t, rec.Body.String(), "event: error\n")
+			}
+		})
+	}
+}
+
+func TestSSEOptionsKeepAlive(t *testing.T) {
+	assert.Equal(t, DefaultSSEKeepAlive, SSEOptions{}.keepAlive())
+	assert.Equal(t, time.Second, SSEOptions{KeepAlive: time.Second}.keepAlive())
+
+	e := echo.New()
+	e.GET("/events", HandleSSE(Handler{}, SSEOptions{KeepAlive: 5 * time.Millisecond},
+		func(c echo.Context, req struct{}, events chan<- Event) error {
+			time.Sleep(30 * time.Millisecond)
+			return nil
+		}))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), ": keepalive\n\n")
+}
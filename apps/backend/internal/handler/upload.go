@@ -0,0 +1,378 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/Mayank85Y/boil/internal/lib/storage"
+	"github.com/Mayank85Y/boil/internal/middleware"
+	"github.com/Mayank85Y/boil/internal/validation"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/newrelic/go-agent/v3/newrelic"
+)
+
+const (
+	// DefaultMaxUploadBytes is the per file limit when UploadOptions.MaxFileBytes is 0
+	DefaultMaxUploadBytes int64 = 10 << 20
+
+	// maxFormValueBytes caps the non-file fields sent next to the files
+	maxFormValueBytes int64 = 1 << 20
+
+	// sniffLen is what http.DetectContentType looks at
+	sniffLen = 512
+)
+
+// UploadOptions limits what a route accepts, every file is checked while it streams into storage
+type UploadOptions struct {
+	// Field is the form field holding the files, files in other fields are rejected. Empty accepts any field.
+	Field string
+	// MaxFiles per request, 0 means 1
+	MaxFiles int
+	// MaxFileBytes per file, 0 means DefaultMaxUploadBytes
+	MaxFileBytes int64
+	// AllowedTypes are matched against the sniffed content type, "image/*" matches any image. Empty allows all.
+	AllowedTypes []string
+	// Prefix is prepended to generated keys e.g. avatars
+	Prefix string
+	// KeyFunc replaces the generated <prefix>/<yyyy>/<mm>/<dd>/<uuid><ext> keys
+	KeyFunc func(c echo.Context, filename string, contentType string) string
+}
+
+func (o UploadOptions) maxFiles() int {
+	if o.MaxFiles <= 0 {
+		return 1
+	}
+	return o.MaxFiles
+}
+
+func (o UploadOptions) maxFileBytes() int64 {
+	if o.MaxFileBytes <= 0 {
+		return DefaultMaxUploadBytes
+	}
+	return o.MaxFileBytes
+}
+
+func (o UploadOptions) key(c echo.Context, filename string, contentType string) string {
+	if o.KeyFunc != nil {
+		return o.KeyFunc(c, filename, contentType)
+	}
+	ext := strings.ToLower(path.Ext(filename))
+	if ext == "" {
+		if exts, _ := mime.ExtensionsByType(contentType); len(exts) > 0 {
+			ext = exts[0]
+		}
+	}
+	return path.Join(o.Prefix, time.Now().UTC().Format("2006/01/02"), uuid.NewString()+ext)
+}
+
+func (o UploadOptions) allows(contentType string) bool {
+	if len(o.AllowedTypes) == 0 {
+		return true
+	}
+	mainType, _, _ := strings.Cut(contentType, "/")
+	for _, allowed := range o.AllowedTypes {
+		if allowed == contentType || allowed == "*/*" || allowed == mainType+"/*" {
+			return true
+		}
+	}
+	return false
+}
+
+// UploadedFile is a file that was stored for the request
+type UploadedFile struct {
+	Field    string `json:"field"`
+	Filename string `json:"filename"` // as sent by the client, never use it as a key
+	storage.Object
+}
+
+// HandlerFuncUpload receives the bound form fields and the files already in storage
+type HandlerFuncUpload[Req any, Res any] func(c echo.Context, req Req, files []UploadedFile) (Res, error)
+
+// HandleUpload wraps a handler with validation, error handling, logging, metrics, and tracing for
+// multipart/form-data uploads. Files are streamed into store part by part, never buffered in memory or
+// on disk, and checked against opts on the way. The other form fields bind into Req through form tags.
+// Stored files are deleted again when validation or the handler fails.
+func HandleUpload[Req any, Res any](
+	h Handler,
+	store storage.Storage,
+	opts UploadOptions,
+	handler HandlerFuncUpload[Req, Res],
+	status int,
+) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		logger := middleware.GetLogger(c)
+		txn := newrelic.FromContext(c.Request().Context())
+
+		files, err := receiveUpload(c, store, opts)
+		if err != nil {
+			logger.Error().Err(err).Int("files", len(files)).Msg("upload failed")
+			deleteUploads(c, store, files)
+			return err
+		}
+
+		var size int64
+		for _, file := range files {
+			size += file.Size
+		}
+		logger.Debug().
+			Int("files", len(files)).
+			Int64("bytes", size).
+			Dur("upload_duration", time.Since(start)).
+			Msg("upload stored")
+		if txn != nil {
+			txn.AddAttribute("upload.files", len(files))
+			txn.AddAttribute("upload.bytes", size)
+		}
+
+		succeeded := false
+		err = handleRequest(c, func(c echo.Context, req Req) (interface{}, error) {
+			result, err := handler(c, req, files)
+			succeeded = err == nil
+			return result, err
+		}, JSONResponseHandler{status: status})
+		if !succeeded {
+			deleteUploads(c, store, files)
+		}
+		return err
+	}
+}
+
+// receiveUpload streams every part of the body, files go to store and the other fields end up in
+// req.MultipartForm so they bind like a parsed form. Files stored before an error are returned with it.
+func receiveUpload(c echo.Context, store storage.Storage, opts UploadOptions) ([]UploadedFile, error) {
+	req := c.Request()
+
+	limit, ok := c.Get(validation.MaxBodyBytesKey).(int64)
+	if !ok {
+		limit = int64(opts.maxFiles())*opts.maxFileBytes() + maxFormValueBytes
+	}
+	req.Body = http.MaxBytesReader(c.Response(), req.Body, limit)
+
+	reader, err := req.MultipartReader()
+	if err != nil {
+		return nil, errs.NewUnsupportedMediaTypeError("Request body must be multipart/form-data", true,
+			[]string{echo.MIMEMultipartForm}).
+			WithMessageKey("upload.not_multipart", nil).
+			WithCause(err)
+	}
+
+	values := url.Values{}
+	valueBytes := int64(0)
+	var files []UploadedFile
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return files, uploadReadError(err)
+		}
+
+		if part.FileName() == "" {
+			value, err := io.ReadAll(io.LimitReader(part, maxFormValueBytes-valueBytes+1))
+			part.Close()
+			if err != nil {
+				return files, uploadReadError(err)
+			}
+			valueBytes += int64(len(value))
+			if valueBytes > maxFormValueBytes {
+				return files, errs.NewRequestTooLargeError("Form fields are too large", true, maxFormValueBytes).
+					WithMessageKey("bind.too_large", map[string]string{"limit": strconv.FormatInt(maxFormValueBytes, 10)})
+			}
+			values.Add(part.FormName(), string(value))
+			continue
+		}
+
+		file, err := storePart(c, store, opts, part, len(files))
+		part.Close()
+		if err != nil {
+			return files, err
+		}
+		files = append(files, file)
+	}
+
+	if len(files) == 0 {
+		field := opts.Field
+		if field == "" {
+			field = "file"
+		}
+		return nil, errs.NewBadRequestError("No file was uploaded", true, nil, []errs.FieldError{
+			uploadFieldError(field, "is required", "validation.required", nil),
+		}, nil).WithMessageKey("upload.missing", nil)
+	}
+
+	// what ParseMultipartForm would have left behind, so binding and c.FormValue keep working
+	req.MultipartForm = &multipart.Form{Value: values, File: map[string][]*multipart.FileHeader{}}
+	req.PostForm = values
+	req.Form = url.Values{}
+	for key, value := range values {
+		req.Form[key] = append(req.Form[key], value...)
+	}
+	for key, value := range req.URL.Query() {
+		req.Form[key] = append(req.Form[key], value...)
+	}
+	return files, nil
+}
+
+func storePart(c echo.Context, store storage.Storage, opts UploadOptions, part *multipart.Part, count int) (UploadedFile, error) {
+	field := part.FormName()
+	if opts.Field != "" && field != opts.Field {
+		return UploadedFile{}, errs.NewBadRequestError("Unexpected file field", true, nil, []errs.FieldError{
+			uploadFieldError(field, "does not accept files", "upload.field.unexpected", nil),
+		}, nil).WithMessageKey("upload.unexpected_field", nil)
+	}
+	if count >= opts.maxFiles() {
+		max := strconv.Itoa(opts.maxFiles())
+		return UploadedFile{}, errs.NewBadRequestError("Too many files", true, nil, []errs.FieldError{
+			uploadFieldError(field, "must not contain more than "+max+" files", "upload.field.too_many", map[string]string{"max": max}),
+		}, nil).WithMessageKey("upload.too_many_files", map[string]string{"max": max})
+	}
+
+	// the header of the part is up to the client, the type is taken from the content itself
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(part, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return UploadedFile{}, uploadReadError(err)
+	}
+	head = head[:n]
+	if n == 0 {
+		return UploadedFile{}, errs.NewBadRequestError("Uploaded file is empty", true, nil, []errs.FieldError{
+			uploadFieldError(field, "must not be empty", "upload.field.empty", nil),
+		}, nil).WithMessageKey("upload.empty", nil)
+	}
+
+	filename := path.Base(strings.ReplaceAll(part.FileName(), "\\", "/"))
+	contentType := sniffContentType(head, filename)
+	if !opts.allows(contentType) {
+		return UploadedFile{}, errs.NewUnsupportedMediaTypeError(
+			fmt.Sprintf("Files of type %s are not allowed", contentType), true, opts.AllowedTypes).
+			WithMessageKey("upload.type_not_allowed", map[string]string{"type": contentType})
+	}
+
+	maxBytes := opts.maxFileBytes()
+	content := &fileLimitReader{r: io.MultiReader(bytes.NewReader(head), part), remaining: maxBytes}
+	object, err := store.Put(c.Request().Context(), opts.key(c, filename, contentType), content, storage.PutOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		// a failed Put removes what it wrote (see storage.Storage), only earlier files need cleaning up
+		if content.exceeded {
+			limit := strconv.FormatInt(maxBytes, 10)
+			return UploadedFile{}, errs.NewRequestTooLargeError(
+				fmt.Sprintf("Files must not exceed %s bytes", limit), true, maxBytes).
+				WithMessageKey("upload.too_large", map[string]string{"limit": limit}).
+				WithCause(err)
+		}
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return UploadedFile{}, uploadReadError(maxBytesErr)
+		}
+		return UploadedFile{}, fmt.Errorf("failed to store upload: %w", err)
+	}
+
+	return UploadedFile{Field: field, Filename: filename, Object: object}, nil
+}
+
+// sniffContentType trusts the content over the file name. Containers and plain text are refined by
+// the extension (application/zip -> .docx, text/plain -> .csv) but never turned into a different kind.
+func sniffContentType(head []byte, filename string) string {
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+
+	byExtension, _, _ := mime.ParseMediaType(mime.TypeByExtension(strings.ToLower(path.Ext(filename))))
+	if byExtension == "" {
+		return sniffed
+	}
+
+	switch sniffed {
+	case "application/octet-stream", "application/zip":
+		if !strings.HasPrefix(byExtension, "text/") {
+			return byExtension
+		}
+	case "text/plain":
+		if strings.HasPrefix(byExtension, "text/") && byExtension != echo.MIMETextHTML {
+			return byExtension
+		}
+	}
+	return sniffed
+}
+
+// fileLimitReader fails once a file grows beyond the per file limit
+type fileLimitReader struct {
+	r         io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+var errFileTooLarge = errors.New("upload exceeds the file size limit")
+
+func (l *fileLimitReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		l.exceeded = true
+		return 0, errFileTooLarge
+	}
+	// read one byte past the limit to tell a file of exactly the limit from a larger one
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		l.exceeded = true
+		return n, errFileTooLarge
+	}
+	return n, err
+}
+
+func uploadReadError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		limit := strconv.FormatInt(maxBytesErr.Limit, 10)
+		return errs.NewRequestTooLargeError(
+			fmt.Sprintf("Request body must not exceed %s bytes", limit), true, maxBytesErr.Limit).
+			WithMessageKey("bind.too_large", map[string]string{"limit": limit}).
+			WithCause(err)
+	}
+	return errs.NewBadRequestError("Request body could not be read", true, nil, nil, nil).
+		WithMessageKey("bind.unreadable", nil).
+		WithCause(err)
+}
+
+func uploadFieldError(field string, message string, key string, params map[string]string) errs.FieldError {
+	return errs.FieldError{
+		Field:    field,
+		Error:    message,
+		Location: errs.FieldLocationBody,
+		Key:      key,
+		Params:   params,
+	}
+}
+
+// deleteUploads removes files of a failed request, it outlives a canceled request context
+func deleteUploads(c echo.Context, store storage.Storage, files []UploadedFile) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request().Context()), 30*time.Second)
+	defer cancel()
+
+	for _, file := range files {
+		if file.Key == "" {
+			continue
+		}
+		if err := store.Delete(ctx, file.Key); err != nil {
+			middleware.GetLogger(c).Error().Err(err).Str("key", file.Key).Msg("failed to delete upload")
+		}
+	}
+}
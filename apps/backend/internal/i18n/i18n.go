@@ -0,0 +1,148 @@
+// Package i18n translates client facing messages. English is the source language: messages are
+// written in English in code and only looked up here when the request asks for another locale.
+// Error codes are never translated so clients can keep branching on them.
+package i18n
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/text/language"
+)
+
+// HeaderAcceptLanguage and HeaderContentLanguage are missing from echo's header constants
+const (
+	HeaderAcceptLanguage  = "Accept-Language"
+	HeaderContentLanguage = "Content-Language"
+)
+
+// Default is the language messages are written in
+var Default = language.English
+
+// UserLocaleKey is the echo context key a profile loader sets to the user's preferred locale (BCP 47, e.g. "de-AT"),
+// it wins over Accept-Language
+const UserLocaleKey = "user_locale"
+
+type localeKey struct{}
+
+type catalog struct {
+	mu        sync.RWMutex
+	messages  map[language.Tag]map[string]string
+	supported []language.Tag //same order as matcher, index 0 is Default
+	matcher   language.Matcher
+}
+
+var messages = &catalog{
+	messages: map[language.Tag]map[string]string{},
+}
+
+func init() {
+	Register(language.German, german)
+	Register(language.Spanish, spanish)
+}
+
+// Register adds translations for a locale, later calls add to or replace earlier keys.
+// Keys are error codes (NOT_FOUND, {ENTITY}_NOT_FOUND), validation tags (validation.required)
+// and message keys set with errs.HTTPError.WithMessageKey. Placeholders look like {param}.
+func Register(tag language.Tag, translations map[string]string) {
+	messages.mu.Lock()
+	defer messages.mu.Unlock()
+
+	existing, ok := messages.messages[tag]
+	if !ok {
+		existing = map[string]string{}
+		messages.messages[tag] = existing
+	}
+	for key, message := range translations {
+		existing[key] = message
+	}
+
+	if len(messages.supported) == 0 {
+		messages.supported = []language.Tag{Default}
+	}
+	if !ok && tag != Default {
+		messages.supported = append(messages.supported, tag)
+	}
+	messages.matcher = language.NewMatcher(messages.supported)
+}
+
+// Supported returns the locales with registered translations, plus the default
+func Supported() []language.Tag {
+	messages.mu.RLock()
+	defer messages.mu.RUnlock()
+
+	if len(messages.supported) == 0 {
+		return []language.Tag{Default}
+	}
+	return append([]language.Tag(nil), messages.supported...)
+}
+
+// Match picks the best supported locale for the user preference and Accept-Language header, in that order
+func Match(userLocale string, acceptLanguage string) language.Tag {
+	messages.mu.RLock()
+	matcher, supported := messages.matcher, messages.supported
+	messages.mu.RUnlock()
+	if matcher == nil {
+		return Default
+	}
+
+	var preferred []language.Tag
+	if userLocale != "" {
+		if tag, err := language.Parse(userLocale); err == nil {
+			preferred = append(preferred, tag)
+		}
+	}
+	if acceptLanguage != "" {
+		if tags, _, err := language.ParseAcceptLanguage(acceptLanguage); err == nil {
+			preferred = append(preferred, tags...)
+		}
+	}
+	if len(preferred) == 0 {
+		return Default
+	}
+
+	_, index, confidence := matcher.Match(preferred...)
+	if confidence == language.No {
+		return Default
+	}
+	return supported[index]
+}
+
+// FromEcho resolves the locale of a request from the user profile and Accept-Language
+func FromEcho(c echo.Context) language.Tag {
+	userLocale, _ := c.Get(UserLocaleKey).(string)
+	return Match(userLocale, c.Request().Header.Get(HeaderAcceptLanguage))
+}
+
+func WithLocale(ctx context.Context, tag language.Tag) context.Context {
+	return context.WithValue(ctx, localeKey{}, tag)
+}
+
+// FromContext returns the locale stored with WithLocale, or Default
+func FromContext(ctx context.Context) language.Tag {
+	if tag, ok := ctx.Value(localeKey{}).(language.Tag); ok {
+		return tag
+	}
+	return Default
+}
+
+// Lookup returns the translation of key with {placeholders} filled from params
+func Lookup(tag language.Tag, key string, params map[string]string) (string, bool) {
+	if tag == Default || key == "" {
+		return "", false
+	}
+
+	messages.mu.RLock()
+	message, ok := messages.messages[tag][key]
+	messages.mu.RUnlock()
+	if !ok {
+		return "", false
+	}
+
+	for name, value := range params {
+		message = strings.ReplaceAll(message, "{"+name+"}", value)
+	}
+	return message, true
+}
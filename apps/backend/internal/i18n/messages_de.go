@@ -0,0 +1,119 @@
+package i18n
+
+var german = map[string]string{
+	// error codes, used when an error has no message key of its own
+	"BAD_REQUEST":              "Die Anfrage ist ungültig",
+	"UNAUTHORIZED":             "Bitte melde dich an",
+	"FORBIDDEN":                "Du hast keine Berechtigung für diese Aktion",
+	"NOT_FOUND":                "Nicht gefunden",
+	"METHOD_NOT_ALLOWED":       "Diese Methode wird nicht unterstützt",
+	"NOT_ACCEPTABLE":           "Das angeforderte Format ist nicht verfügbar",
+	"REQUEST_TIMEOUT":          "Die Anfrage hat zu lange gedauert",
+	"CONFLICT":                 "Die Anfrage steht im Konflikt mit dem aktuellen Zustand",
+	"GONE":                     "Die Ressource ist nicht mehr verfügbar",
+	"PRECONDITION_FAILED":      "Die Ressource wurde inzwischen geändert",
+	"REQUEST_ENTITY_TOO_LARGE": "Die Anfrage ist zu groß",
+	"UNSUPPORTED_MEDIA_TYPE":   "Der Inhaltstyp wird nicht unterstützt",
+	"UNPROCESSABLE_ENTITY":     "Die Anfrage konnte nicht verarbeitet werden",
+	"TOO_MANY_REQUESTS":        "Zu viele Anfragen, bitte versuche es gleich noch einmal",
+	"INTERNAL_SERVER_ERROR":    "Ein unerwarteter Fehler ist aufgetreten",
+	"NOT_IMPLEMENTED":          "Diese Funktion ist nicht verfügbar",
+	"BAD_GATEWAY":              "Ein abhängiger Dienst hat ungültig geantwortet",
+	"SERVICE_UNAVAILABLE":      "Der Dienst ist vorübergehend nicht verfügbar, bitte versuche es später erneut",
+	"GATEWAY_TIMEOUT":          "Ein abhängiger Dienst hat nicht rechtzeitig geantwortet",
+	"{ENTITY}_NOT_FOUND":       "{entity} wurde nicht gefunden",
+	"{ENTITY}_ALREADY_EXISTS":  "{entity} existiert bereits",
+	"{ENTITY}_REQUIRED":        "Ein Pflichtfeld fehlt",
+	"{ENTITY}_INVALID":         "Ein oder mehrere Werte erfüllen die Bedingungen nicht",
+	"{ENTITY}_CONFLICT":        "{entity} steht im Konflikt mit einem vorhandenen Eintrag",
+	"VALUE_TOO_LONG":           "Ein oder mehrere Werte sind zu lang",
+	"INVALID_FORMAT":           "Ein oder mehrere Werte haben ein ungültiges Format",
+	"INVALID_DATA":             "Ein oder mehrere Werte sind ungültig",
+	"TRANSACTION_CONFLICT":     "Die Ressource wird gerade von einer anderen Anfrage geändert, bitte versuche es erneut",
+	"RESOURCE_LOCKED":          "Die Ressource ist gerade gesperrt, bitte versuche es erneut",
+	"QUERY_TIMEOUT":            "Die Anfrage hat zu lange gedauert, bitte versuche es erneut",
+
+	// database errors, see sqlerr
+	"sqlerr.foreign_key":    "{entity} existiert nicht",
+	"sqlerr.unique":         "{entity} mit dieser Kennung existiert bereits",
+	"sqlerr.unique_field":   "{entity} mit diesem Wert für {field} existiert bereits",
+	"sqlerr.not_null":       "{field} ist erforderlich",
+	"sqlerr.check":          "Ein oder mehrere Werte erfüllen die Bedingungen nicht",
+	"sqlerr.check_field":    "Der Wert für {field} erfüllt die Bedingungen nicht",
+	"sqlerr.exclude":        "{entity} steht im Konflikt mit einem vorhandenen Eintrag",
+	"sqlerr.too_long":       "Ein oder mehrere Werte sind zu lang",
+	"sqlerr.too_long_field": "Der Wert für {field} ist zu lang",
+	"sqlerr.invalid_format": "Ein oder mehrere Werte haben ein ungültiges Format",
+	"sqlerr.invalid_data":   "Ein oder mehrere Werte sind ungültig",
+	"sqlerr.conflict":       "Die Ressource wird gerade von einer anderen Anfrage geändert, bitte versuche es erneut",
+	"sqlerr.timeout":        "Die Anfrage hat zu lange gedauert, bitte versuche es erneut",
+	"sqlerr.unavailable":    "Der Dienst ist vorübergehend nicht verfügbar, bitte versuche es später erneut",
+	"sqlerr.not_found":      "{entity} wurde nicht gefunden",
+	"field.required":        "ist erforderlich",
+	"field.already_exists":  "existiert bereits",
+	"field.not_exists":      "existiert nicht",
+	"field.conflict":        "steht im Konflikt mit einem vorhandenen Eintrag",
+	"field.invalid":         "ist ungültig",
+	"field.too_long":        "ist zu lang",
+	"field.invalid_format":  "hat ein ungültiges Format",
+
+	// request binding, see validation/bind.go
+	"bind.invalid_json":   "Der Anfrageinhalt ist kein gültiges JSON",
+	"bind.invalid_type":   "Der Anfrageinhalt enthält einen falschen Typ",
+	"bind.unknown_fields": "Der Anfrageinhalt enthält unbekannte Felder",
+	"bind.too_large":      "Der Anfrageinhalt darf höchstens {limit} Bytes groß sein",
+	"bind.unreadable":     "Der Anfrageinhalt konnte nicht gelesen werden",
+	"bind.field.type":     "muss vom Typ {type} sein, erhalten: {value}",
+	"bind.field.syntax":   "ist an Byte {offset} fehlerhaft",
+	"bind.field.eof":      "endet unerwartet",
+	"bind.field.unknown":  "ist nicht erlaubt",
+	"bind.field.single":   "darf nur einen JSON-Wert enthalten",
+
+	// uploads, see handler/upload.go
+	"upload.not_multipart":    "Der Anfrageinhalt muss multipart/form-data sein",
+	"upload.missing":          "Es wurde keine Datei hochgeladen",
+	"upload.unexpected_field": "Unerwartetes Dateifeld",
+	"upload.too_many_files":   "Es dürfen höchstens {max} Dateien hochgeladen werden",
+	"upload.empty":            "Die hochgeladene Datei ist leer",
+	"upload.type_not_allowed": "Dateien vom Typ {type} sind nicht erlaubt",
+	"upload.too_large":        "Dateien dürfen höchstens {limit} Bytes groß sein",
+	"upload.field.unexpected": "nimmt keine Dateien an",
+	"upload.field.too_many":   "darf höchstens {max} Dateien enthalten",
+	"upload.field.empty":      "darf nicht leer sein",
+
+	// idempotency keys, see middleware/idempotency.go
+	"idempotency.required": "Der Header Idempotency-Key ist erforderlich",
+	"idempotency.invalid":  "Der Header Idempotency-Key ist ungültig, erlaubt sind höchstens {max} sichtbare ASCII-Zeichen",
+	"idempotency.in_use":   "Eine Anfrage mit diesem Idempotency-Key wird noch verarbeitet",
+	"idempotency.reused":   "Der Idempotency-Key wurde bereits für eine andere Anfrage verwendet",
+
+	// validation tags, see validation/validator.go
+	"validation.failed":           "Die Validierung ist fehlgeschlagen",
+	"validation.required":         "ist erforderlich",
+	"validation.min.string":       "muss mindestens {param} Zeichen lang sein",
+	"validation.min.collection":   "muss mindestens {param} Einträge enthalten",
+	"validation.min.number":       "muss mindestens {param} sein",
+	"validation.max.string":       "darf höchstens {param} Zeichen lang sein",
+	"validation.max.collection":   "darf höchstens {param} Einträge enthalten",
+	"validation.max.number":       "darf höchstens {param} sein",
+	"validation.len.string":       "muss genau {param} Zeichen lang sein",
+	"validation.len.collection":   "muss genau {param} Einträge enthalten",
+	"validation.len.number":       "muss genau {param} sein",
+	"validation.gt":               "muss größer als {param} sein",
+	"validation.gte":              "muss mindestens {param} sein",
+	"validation.lt":               "muss kleiner als {param} sein",
+	"validation.lte":              "darf höchstens {param} sein",
+	"validation.oneof":            "muss einer der folgenden Werte sein: {param}",
+	"validation.email":            "muss eine gültige E-Mail-Adresse sein",
+	"validation.url":              "muss eine gültige URL sein",
+	"validation.e164":             "muss eine gültige Telefonnummer mit Ländervorwahl sein",
+	"validation.uuid":             "muss eine gültige UUID sein",
+	"validation.iso3166_1_alpha2": "muss ein gültiger ISO-3166-1-Alpha-2-Ländercode sein",
+	"validation.country":          "muss ein gültiger ISO-3166-1-Alpha-2-Ländercode sein",
+	"validation.uuidList":         "muss eine kommagetrennte Liste gültiger UUIDs sein",
+	"validation.slug":             "darf nur Kleinbuchstaben, Ziffern und einzelne Bindestriche enthalten",
+	"validation.timezone":         "muss eine gültige IANA-Zeitzone wie Europe/Berlin sein",
+	"validation.strong_password":  "muss mindestens 8 Zeichen lang sein und Groß- und Kleinbuchstaben, eine Ziffer und ein Sonderzeichen enthalten",
+	"validation.future_date":      "muss in der Zukunft liegen",
+	"validation.dive":             "einige Einträge sind ungültig",
+}
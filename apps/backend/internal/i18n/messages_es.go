@@ -0,0 +1,119 @@
+package i18n
+
+var spanish = map[string]string{
+	// error codes, used when an error has no message key of its own
+	"BAD_REQUEST":              "La solicitud no es válida",
+	"UNAUTHORIZED":             "Inicia sesión para continuar",
+	"FORBIDDEN":                "No tienes permiso para realizar esta acción",
+	"NOT_FOUND":                "No encontrado",
+	"METHOD_NOT_ALLOWED":       "Este método no está permitido",
+	"NOT_ACCEPTABLE":           "El formato solicitado no está disponible",
+	"REQUEST_TIMEOUT":          "La solicitud tardó demasiado",
+	"CONFLICT":                 "La solicitud entra en conflicto con el estado actual",
+	"GONE":                     "El recurso ya no está disponible",
+	"PRECONDITION_FAILED":      "El recurso ha sido modificado entretanto",
+	"REQUEST_ENTITY_TOO_LARGE": "La solicitud es demasiado grande",
+	"UNSUPPORTED_MEDIA_TYPE":   "El tipo de contenido no es compatible",
+	"UNPROCESSABLE_ENTITY":     "No se pudo procesar la solicitud",
+	"TOO_MANY_REQUESTS":        "Demasiadas solicitudes, inténtalo de nuevo en un momento",
+	"INTERNAL_SERVER_ERROR":    "Se produjo un error inesperado",
+	"NOT_IMPLEMENTED":          "Esta función no está disponible",
+	"BAD_GATEWAY":              "Un servicio externo devolvió una respuesta no válida",
+	"SERVICE_UNAVAILABLE":      "El servicio no está disponible temporalmente, inténtalo más tarde",
+	"GATEWAY_TIMEOUT":          "Un servicio externo no respondió a tiempo",
+	"{ENTITY}_NOT_FOUND":       "No se encontró {entity}",
+	"{ENTITY}_ALREADY_EXISTS":  "{entity} ya existe",
+	"{ENTITY}_REQUIRED":        "Falta un campo obligatorio",
+	"{ENTITY}_INVALID":         "Uno o más valores no cumplen las condiciones requeridas",
+	"{ENTITY}_CONFLICT":        "{entity} entra en conflicto con un registro existente",
+	"VALUE_TOO_LONG":           "Uno o más valores son demasiado largos",
+	"INVALID_FORMAT":           "Uno o más valores tienen un formato no válido",
+	"INVALID_DATA":             "Uno o más valores no son válidos",
+	"TRANSACTION_CONFLICT":     "Otra solicitud está modificando el recurso, inténtalo de nuevo",
+	"RESOURCE_LOCKED":          "El recurso está bloqueado, inténtalo de nuevo",
+	"QUERY_TIMEOUT":            "La solicitud tardó demasiado, inténtalo de nuevo",
+
+	// database errors, see sqlerr
+	"sqlerr.foreign_key":    "{entity} no existe",
+	"sqlerr.unique":         "Ya existe {entity} con este identificador",
+	"sqlerr.unique_field":   "Ya existe {entity} con este valor de {field}",
+	"sqlerr.not_null":       "{field} es obligatorio",
+	"sqlerr.check":          "Uno o más valores no cumplen las condiciones requeridas",
+	"sqlerr.check_field":    "El valor de {field} no cumple las condiciones requeridas",
+	"sqlerr.exclude":        "{entity} entra en conflicto con un registro existente",
+	"sqlerr.too_long":       "Uno o más valores son demasiado largos",
+	"sqlerr.too_long_field": "El valor de {field} es demasiado largo",
+	"sqlerr.invalid_format": "Uno o más valores tienen un formato no válido",
+	"sqlerr.invalid_data":   "Uno o más valores no son válidos",
+	"sqlerr.conflict":       "Otra solicitud está modificando el recurso, inténtalo de nuevo",
+	"sqlerr.timeout":        "La solicitud tardó demasiado, inténtalo de nuevo",
+	"sqlerr.unavailable":    "El servicio no está disponible temporalmente, inténtalo más tarde",
+	"sqlerr.not_found":      "No se encontró {entity}",
+	"field.required":        "es obligatorio",
+	"field.already_exists":  "ya existe",
+	"field.not_exists":      "no existe",
+	"field.conflict":        "entra en conflicto con un registro existente",
+	"field.invalid":         "no es válido",
+	"field.too_long":        "es demasiado largo",
+	"field.invalid_format":  "tiene un formato no válido",
+
+	// request binding, see validation/bind.go
+	"bind.invalid_json":   "El cuerpo de la solicitud no es JSON válido",
+	"bind.invalid_type":   "El cuerpo de la solicitud contiene un tipo incorrecto",
+	"bind.unknown_fields": "El cuerpo de la solicitud contiene campos desconocidos",
+	"bind.too_large":      "El cuerpo de la solicitud no puede superar {limit} bytes",
+	"bind.unreadable":     "No se pudo leer el cuerpo de la solicitud",
+	"bind.field.type":     "debe ser de tipo {type}, se recibió {value}",
+	"bind.field.syntax":   "tiene un error en el byte {offset}",
+	"bind.field.eof":      "termina de forma inesperada",
+	"bind.field.unknown":  "no está permitido",
+	"bind.field.single":   "debe contener un único valor JSON",
+
+	// uploads, see handler/upload.go
+	"upload.not_multipart":    "El cuerpo de la solicitud debe ser multipart/form-data",
+	"upload.missing":          "No se subió ningún archivo",
+	"upload.unexpected_field": "Campo de archivo inesperado",
+	"upload.too_many_files":   "No se pueden subir más de {max} archivos",
+	"upload.empty":            "El archivo subido está vacío",
+	"upload.type_not_allowed": "Los archivos de tipo {type} no están permitidos",
+	"upload.too_large":        "Los archivos no pueden superar {limit} bytes",
+	"upload.field.unexpected": "no acepta archivos",
+	"upload.field.too_many":   "no puede contener más de {max} archivos",
+	"upload.field.empty":      "no puede estar vacío",
+
+	// idempotency keys, see middleware/idempotency.go
+	"idempotency.required": "El encabezado Idempotency-Key es obligatorio",
+	"idempotency.invalid":  "El encabezado Idempotency-Key no es válido, se permiten como máximo {max} caracteres ASCII visibles",
+	"idempotency.in_use":   "Una solicitud con esta Idempotency-Key todavía se está procesando",
+	"idempotency.reused":   "La Idempotency-Key ya se utilizó para una solicitud diferente",
+
+	// validation tags, see validation/validator.go
+	"validation.failed":           "La validación ha fallado",
+	"validation.required":         "es obligatorio",
+	"validation.min.string":       "debe tener al menos {param} caracteres",
+	"validation.min.collection":   "debe contener al menos {param} elementos",
+	"validation.min.number":       "debe ser al menos {param}",
+	"validation.max.string":       "no puede superar {param} caracteres",
+	"validation.max.collection":   "no puede contener más de {param} elementos",
+	"validation.max.number":       "no puede superar {param}",
+	"validation.len.string":       "debe tener exactamente {param} caracteres",
+	"validation.len.collection":   "debe contener exactamente {param} elementos",
+	"validation.len.number":       "debe ser exactamente {param}",
+	"validation.gt":               "debe ser mayor que {param}",
+	"validation.gte":              "debe ser al menos {param}",
+	"validation.lt":               "debe ser menor que {param}",
+	"validation.lte":              "no puede superar {param}",
+	"validation.oneof":            "debe ser uno de: {param}",
+	"validation.email":            "debe ser un correo electrónico válido",
+	"validation.url":              "debe ser una URL válida",
+	"validation.e164":             "debe ser un número de teléfono válido con prefijo de país",
+	"validation.uuid":             "debe ser un UUID válido",
+	"validation.iso3166_1_alpha2": "debe ser un código de país ISO 3166-1 alfa-2 válido",
+	"validation.country":          "debe ser un código de país ISO 3166-1 alfa-2 válido",
+	"validation.uuidList":         "debe ser una lista de UUID válidos separados por comas",
+	"validation.slug":             "solo puede contener minúsculas, números y guiones simples",
+	"validation.timezone":         "debe ser una zona horaria IANA válida como Europe/Madrid",
+	"validation.strong_password":  "debe tener al menos 8 caracteres e incluir mayúsculas, minúsculas, un número y un símbolo",
+	"validation.future_date":      "debe estar en el futuro",
+	"validation.dive":             "algunos elementos no son válidos",
+}
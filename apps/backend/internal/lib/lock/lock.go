@@ -0,0 +1,167 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNotAcquired is returned when the lock is held by someone else and could not
+	// be obtained before the context was done.
+	ErrNotAcquired = errors.New("lock: not acquired")
+
+	// ErrNotHeld is returned when refreshing or releasing a lock that already expired
+	// or was released.
+	ErrNotHeld = errors.New("lock: not held")
+
+	// ErrInvalidTTL is returned for TTLs below MinTTL
+	ErrInvalidTTL = errors.New("lock: invalid ttl")
+)
+
+const (
+	DefaultRetryInterval = 100 * time.Millisecond
+	DefaultKeyPrefix     = "lock:"
+
+	// MinTTL is the smallest TTL every backend can honour, Redis expires keys with millisecond precision
+	MinTTL = time.Millisecond
+)
+
+// Options tunes the behaviour shared by every Locker implementation
+type Options struct {
+	// RetryInterval is how long Acquire waits between attempts
+	RetryInterval time.Duration
+	// AcquireTimeout bounds Acquire when the caller's context has no deadline (0 = wait for ctx)
+	AcquireTimeout time.Duration
+	// KeyPrefix namespaces lock keys so they don't collide with other keys in the backend
+	KeyPrefix string
+}
+
+func DefaultOptions() Options {
+	return Options{
+		RetryInterval:  DefaultRetryInterval,
+		AcquireTimeout: 0,
+		KeyPrefix:      DefaultKeyPrefix,
+	}
+}
+
+// Locker hands out distributed mutual-exclusion locks identified by a key
+type Locker interface {
+	// Acquire blocks until the lock is obtained, the context is done or AcquireTimeout elapses
+	Acquire(ctx context.Context, key string, ttl time.Duration) (Lock, error)
+	// TryAcquire makes a single attempt and returns ErrNotAcquired if the lock is taken
+	TryAcquire(ctx context.Context, key string, ttl time.Duration) (Lock, error)
+}
+
+// Lock is a held lock. It expires after its TTL unless refreshed.
+//
+// RedisLocker expires the key on the server. PostgresLocker only enforces the TTL with a timer in the
+// holding process: advisory locks belong to the session, so a process that stalls (GC pause, stopped
+// container) while its connection stays open keeps the lock past its TTL. Postgres frees it when the
+// session ends, e.g. when the process dies or the connection is dropped.
+type Lock interface {
+	Key() string
+	// Token is a fencing token that increases with every successful acquisition of the key.
+	// Pass it to downstream writes so stale holders can be rejected.
+	Token() int64
+	// Refresh extends the lock so that it expires ttl from now
+	Refresh(ctx context.Context, ttl time.Duration) error
+	// Release gives up the lock; releasing an expired lock returns ErrNotHeld
+	Release(ctx context.Context) error
+}
+
+func checkTTL(ttl time.Duration) error {
+	if ttl < MinTTL {
+		return fmt.Errorf("%w: %s is below %s", ErrInvalidTTL, ttl, MinTTL)
+	}
+	return nil
+}
+
+// acquire retries try until it succeeds or the context (bounded by opts.AcquireTimeout) is done
+func acquire(ctx context.Context, opts Options, try func(ctx context.Context) (Lock, error)) (Lock, error) {
+	if opts.AcquireTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.AcquireTimeout)
+		defer cancel()
+	}
+
+	retryInterval := opts.RetryInterval
+	if retryInterval <= 0 {
+		retryInterval = DefaultRetryInterval
+	}
+
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+
+	for {
+		l, err := try(ctx)
+		if err == nil {
+			return l, nil
+		}
+		if !errors.Is(err, ErrNotAcquired) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %w", ErrNotAcquired, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// WithLock runs fn while holding key, refreshing the lock every ttl/2.
+// If a refresh fails the context passed to fn is cancelled so the work can stop early.
+// A ttl below MinTTL returns ErrInvalidTTL without running fn.
+func WithLock(ctx context.Context, locker Locker, key string, ttl time.Duration, fn func(ctx context.Context) error) error {
+	if err := checkTTL(ttl); err != nil {
+		return err
+	}
+
+	l, err := locker.Acquire(ctx, key, ttl)
+	if err != nil {
+		return err
+	}
+
+	fnCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(ttl / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-fnCtx.Done():
+				return
+			case <-ticker.C:
+				if err := l.Refresh(fnCtx, ttl); err != nil {
+					cancel(fmt.Errorf("failed to refresh lock %s: %w", key, err))
+					return
+				}
+			}
+		}
+	}()
+
+	fnErr := fn(fnCtx)
+	close(done)
+
+	// release with a fresh context so a cancelled parent doesn't leave the lock behind
+	releaseCtx, releaseCancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer releaseCancel()
+	releaseErr := l.Release(releaseCtx)
+
+	if fnErr != nil {
+		return fnErr
+	}
+	if cause := context.Cause(fnCtx); cause != nil && !errors.Is(cause, context.Canceled) {
+		return cause
+	}
+	if releaseErr != nil && !errors.Is(releaseErr, ErrNotHeld) {
+		return releaseErr
+	}
+	return nil
+}
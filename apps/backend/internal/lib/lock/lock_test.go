@@ -0,0 +1,66 @@
+package lock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Mayank85Y/boil/internal/lib/lock"
+	testhelpers "github.com/Mayank85Y/boil/internal/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresLocker(t *testing.T) {
+	testDB, cleanup := testhelpers.SetupTestDB(t)
+	defer cleanup()
+
+	testhelpers.RunLockerSuite(t, testhelpers.NewTestPostgresLocker(testDB))
+}
+
+func TestRedisLocker(t *testing.T) {
+	testhelpers.RunLockerSuite(t, testhelpers.NewTestRedisLocker(testhelpers.SetupTestRedis(t)))
+}
+
+func TestRedisLockerFence(t *testing.T) {
+	ctx := context.Background()
+	client := testhelpers.SetupTestRedis(t)
+	locker := testhelpers.NewTestRedisLocker(client)
+	key := "test-" + uuid.New().String()
+	fenceKey := lock.DefaultKeyPrefix + key + ":fence"
+
+	held, err := locker.TryAcquire(ctx, key, time.Second)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err := locker.TryAcquire(ctx, key, time.Second)
+		require.ErrorIs(t, err, lock.ErrNotAcquired)
+	}
+	fence, err := client.Get(ctx, fenceKey).Int64()
+	require.NoError(t, err)
+	require.Equal(t, held.Token(), fence, "losing attempts don't advance the fence")
+
+	ttl, err := client.PTTL(ctx, fenceKey).Result()
+	require.NoError(t, err)
+	require.Greater(t, ttl, time.Duration(0), "the fence expires with the lock")
+	require.NoError(t, held.Release(ctx))
+
+	// once the counter is gone tokens still increase
+	require.NoError(t, client.Del(ctx, fenceKey).Err())
+	next, err := locker.TryAcquire(ctx, key, time.Second)
+	require.NoError(t, err)
+	defer next.Release(ctx)
+	require.Greater(t, next.Token(), held.Token())
+}
+
+func TestWithLockRejectsInvalidTTL(t *testing.T) {
+	for _, ttl := range []time.Duration{-time.Second, 0, time.Nanosecond} {
+		called := false
+		err := lock.WithLock(context.Background(), nil, "key", ttl, func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+		require.ErrorIs(t, err, lock.ErrInvalidTTL, ttl)
+		require.False(t, called)
+	}
+}
@@ -0,0 +1,202 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/Mayank85Y/boil/internal/database"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// PostgresLocker implements Locker with session level advisory locks.
+// Each held lock pins one connection from the pool until it is released or expires.
+type PostgresLocker struct {
+	db     *database.Database
+	logger *zerolog.Logger
+	opts   Options
+}
+
+func NewPostgresLocker(db *database.Database, logger *zerolog.Logger, opts Options) *PostgresLocker {
+	return &PostgresLocker{
+		db:     db,
+		logger: logger,
+		opts:   opts,
+	}
+}
+
+func (l *PostgresLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (Lock, error) {
+	return acquire(ctx, l.opts, func(ctx context.Context) (Lock, error) {
+		return l.TryAcquire(ctx, key, ttl)
+	})
+}
+
+func (l *PostgresLocker) TryAcquire(ctx context.Context, key string, ttl time.Duration) (Lock, error) {
+	if err := checkTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	conn, err := l.db.Pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection for lock %s: %w", key, err)
+	}
+
+	id := advisoryKey(l.opts.KeyPrefix + key)
+
+	var ok bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", id).Scan(&ok); err != nil {
+		conn.Release()
+		return nil, fmt.Errorf("failed to try advisory lock %s: %w", key, err)
+	}
+	if !ok {
+		conn.Release()
+		return nil, ErrNotAcquired
+	}
+
+	// txid_current is monotonic across the cluster which makes it a usable fencing token
+	var token int64
+	if err := conn.QueryRow(ctx, "SELECT txid_current()").Scan(&token); err != nil {
+		unlockAdvisory(conn, id)
+		conn.Release()
+		return nil, fmt.Errorf("failed to generate fencing token for lock %s: %w", key, err)
+	}
+
+	pl := &postgresLock{
+		key:       key,
+		id:        id,
+		token:     token,
+		conn:      conn,
+		logger:    l.logger,
+		expiresAt: time.Now().Add(ttl),
+	}
+	pl.timer = time.AfterFunc(ttl, pl.expire)
+
+	return pl, nil
+}
+
+type postgresLock struct {
+	mu        sync.Mutex
+	key       string
+	id        int64
+	token     int64
+	conn      *pgxpool.Conn
+	logger    *zerolog.Logger
+	timer     *time.Timer
+	expiresAt time.Time
+	released  bool
+}
+
+func (pl *postgresLock) Key() string {
+	return pl.key
+}
+
+func (pl *postgresLock) Token() int64 {
+	return pl.token
+}
+
+func (pl *postgresLock) Refresh(ctx context.Context, ttl time.Duration) error {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+
+	if pl.released {
+		return ErrNotHeld
+	}
+
+	// make sure the session still holds the lock before extending it
+	var held bool
+	err := pl.conn.QueryRow(ctx,
+		`SELECT EXISTS (
+			SELECT 1 FROM pg_locks
+			WHERE locktype = 'advisory' AND pid = pg_backend_pid() AND objsubid = 1 AND granted
+			AND classid = (($1::bigint >> 32) & 4294967295)::oid
+			AND objid = ($1::bigint & 4294967295)::oid
+		)`,
+		pl.id,
+	).Scan(&held)
+	if err != nil {
+		return fmt.Errorf("failed to refresh lock %s: %w", pl.key, err)
+	}
+	if !held {
+		pl.releaseLocked()
+		return ErrNotHeld
+	}
+
+	pl.expiresAt = time.Now().Add(ttl)
+	pl.timer.Reset(ttl)
+	return nil
+}
+
+func (pl *postgresLock) Release(ctx context.Context) error {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+
+	if pl.released {
+		return ErrNotHeld
+	}
+	pl.timer.Stop()
+
+	var ok bool
+	err := pl.conn.QueryRow(ctx, "SELECT pg_advisory_unlock($1)", pl.id).Scan(&ok)
+	pl.released = true
+	if err != nil {
+		// the session may still hold the lock, drop the connection so postgres frees it
+		_ = pl.conn.Conn().Close(context.Background())
+		pl.conn.Release()
+		return fmt.Errorf("failed to release lock %s: %w", pl.key, err)
+	}
+	pl.conn.Release()
+
+	if !ok {
+		return ErrNotHeld
+	}
+	return nil
+}
+
+// expire runs when the ttl timer fires and frees the lock unless it was refreshed meanwhile
+func (pl *postgresLock) expire() {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+
+	if pl.released {
+		return
+	}
+	if remaining := time.Until(pl.expiresAt); remaining > 0 {
+		pl.timer.Reset(remaining)
+		return
+	}
+
+	pl.logger.Warn().
+		Str("lock", pl.key).
+		Int64("token", pl.token).
+		Msg("advisory lock expired before it was released")
+
+	pl.releaseLocked()
+}
+
+// releaseLocked unlocks and returns the connection; callers must hold pl.mu
+func (pl *postgresLock) releaseLocked() {
+	pl.released = true
+	pl.timer.Stop()
+	unlockAdvisory(pl.conn, pl.id)
+	pl.conn.Release()
+}
+
+func unlockAdvisory(conn *pgxpool.Conn, id int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", id); err != nil {
+		// closing the session is the only other way to free a session level lock
+		_ = conn.Conn().Close(ctx)
+	}
+}
+
+// advisoryKey maps a string key onto the bigint space used by pg_advisory_lock
+func advisoryKey(key string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return int64(h.Sum64())
+}
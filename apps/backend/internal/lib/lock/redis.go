@@ -0,0 +1,123 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// only touch the key when it still holds our token so an expired holder can't free someone else's lock.
+// acquireScript checks, counts and sets in one step so losing attempts don't burn fencing tokens. Tokens
+// never go below the server clock in microseconds, which keeps them increasing after the counter expires.
+var (
+	acquireScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return 0
+end
+local now = redis.call("TIME")
+local token = tonumber(now[1]) * 1000000 + tonumber(now[2])
+local last = tonumber(redis.call("GET", KEYS[2]) or "0")
+if token <= last then
+	token = last + 1
+end
+redis.call("SET", KEYS[2], string.format("%d", token), "PX", ARGV[1])
+redis.call("SET", KEYS[1], string.format("%d", token), "PX", ARGV[1])
+return token`)
+
+	refreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// RedisLocker implements Locker with a key per lock that expires after its TTL and a per-key counter
+// for fencing tokens, the counter expires along with the lock
+type RedisLocker struct {
+	client *redis.Client
+	opts   Options
+}
+
+func NewRedisLocker(client *redis.Client, opts Options) *RedisLocker {
+	return &RedisLocker{
+		client: client,
+		opts:   opts,
+	}
+}
+
+func (l *RedisLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (Lock, error) {
+	return acquire(ctx, l.opts, func(ctx context.Context) (Lock, error) {
+		return l.TryAcquire(ctx, key, ttl)
+	})
+}
+
+func (l *RedisLocker) TryAcquire(ctx context.Context, key string, ttl time.Duration) (Lock, error) {
+	if err := checkTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	lockKey := l.opts.KeyPrefix + key
+
+	token, err := acquireScript.Run(ctx, l.client, []string{lockKey, lockKey + ":fence"}, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+	if token == 0 {
+		return nil, ErrNotAcquired
+	}
+
+	return &redisLock{
+		key:     key,
+		lockKey: lockKey,
+		token:   token,
+		client:  l.client,
+	}, nil
+}
+
+type redisLock struct {
+	key     string
+	lockKey string
+	token   int64
+	client  *redis.Client
+}
+
+func (rl *redisLock) Key() string {
+	return rl.key
+}
+
+func (rl *redisLock) Token() int64 {
+	return rl.token
+}
+
+func (rl *redisLock) Refresh(ctx context.Context, ttl time.Duration) error {
+	res, err := refreshScript.Run(ctx, rl.client, []string{rl.lockKey},
+		strconv.FormatInt(rl.token, 10), ttl.Milliseconds()).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to refresh lock %s: %w", rl.key, err)
+	}
+	if res == 0 {
+		return ErrNotHeld
+	}
+	return nil
+}
+
+func (rl *redisLock) Release(ctx context.Context) error {
+	res, err := releaseScript.Run(ctx, rl.client, []string{rl.lockKey},
+		strconv.FormatInt(rl.token, 10)).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", rl.key, err)
+	}
+	if res == 0 {
+		return ErrNotHeld
+	}
+	return nil
+}
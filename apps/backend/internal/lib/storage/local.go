@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LocalStorage keeps objects as files below a root directory, metadata lives in <root>/.meta/<key>.json.
+// Content and metadata go to temporary files that are renamed into place once both are written,
+// readers never see partial objects and a failed Put keeps the previous one.
+type LocalStorage struct {
+	root string
+}
+
+func NewLocalStorage(root string) (*LocalStorage, error) {
+	if root == "" {
+		return nil, errors.New("storage: local path is required")
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve storage root: %w", err)
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage root: %w", err)
+	}
+	return &LocalStorage{root: root}, nil
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, r io.Reader, opts PutOptions) (Object, error) {
+	key, err := objectKey(key)
+	if err != nil {
+		return Object{}, err
+	}
+
+	target := s.objectPath(key)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return Object{}, fmt.Errorf("failed to create directory for %s: %w", key, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return Object{}, fmt.Errorf("failed to create temporary file for %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	content := newChecksumReader(r)
+	if _, err := io.Copy(tmp, contextReader{ctx: ctx, r: content}); err != nil {
+		return Object{}, fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return Object{}, fmt.Errorf("failed to write %s: %w", key, err)
+	}
+
+	info, err := os.Stat(tmp.Name())
+	if err != nil {
+		return Object{}, fmt.Errorf("failed to stat %s: %w", key, err)
+	}
+	object := Object{
+		Key:         key,
+		Size:        content.size,
+		ContentType: opts.ContentType,
+		Checksum:    content.Checksum(),
+		ModTime:     info.ModTime().UTC(),
+	}
+
+	// both files are complete before either replaces anything, a failure up to here leaves the old object as it was
+	metaTmp, err := s.writeMetaTemp(object)
+	if err != nil {
+		return Object{}, err
+	}
+	defer os.Remove(metaTmp)
+
+	metaPath := s.metaPath(key)
+	previousMeta, previousErr := os.ReadFile(metaPath)
+	if err := os.Rename(metaTmp, metaPath); err != nil {
+		return Object{}, fmt.Errorf("failed to write metadata of %s: %w", key, err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		// the old object is still in place, give it back its own metadata
+		if previousErr == nil {
+			_ = os.WriteFile(metaPath, previousMeta, 0o644)
+		} else {
+			_ = os.Remove(metaPath)
+		}
+		return Object{}, fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	return object, nil
+}
+
+func (s *LocalStorage) Get(ctx context.Context, key string) (io.ReadSeekCloser, Object, error) {
+	object, err := s.Stat(ctx, key)
+	if err != nil {
+		return nil, Object{}, err
+	}
+
+	file, err := os.Open(s.objectPath(object.Key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, Object{}, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	if err != nil {
+		return nil, Object{}, fmt.Errorf("failed to open %s: %w", key, err)
+	}
+	return file, object, nil
+}
+
+func (s *LocalStorage) Stat(ctx context.Context, key string) (Object, error) {
+	key, err := objectKey(key)
+	if err != nil {
+		return Object{}, err
+	}
+
+	info, err := os.Stat(s.objectPath(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Object{}, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	if err != nil {
+		return Object{}, fmt.Errorf("failed to stat %s: %w", key, err)
+	}
+	if info.IsDir() {
+		return Object{}, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+
+	object := Object{Key: key, Size: info.Size(), ModTime: info.ModTime().UTC()}
+	// objects written before the sidecar existed still work, they just lack type and checksum
+	if raw, err := os.ReadFile(s.metaPath(key)); err == nil {
+		var meta objectMeta
+		if err := json.Unmarshal(raw, &meta); err == nil {
+			object.ContentType = meta.ContentType
+			object.Checksum = meta.Checksum
+		}
+	}
+	return object, nil
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	key, err := objectKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(s.objectPath(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	if err := os.Remove(s.metaPath(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete metadata of %s: %w", key, err)
+	}
+	return nil
+}
+
+// writeMetaTemp writes the sidecar of object next to where it belongs and returns the temporary path
+func (s *LocalStorage) writeMetaTemp(object Object) (string, error) {
+	dir := filepath.Dir(s.metaPath(object.Key))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create metadata directory for %s: %w", object.Key, err)
+	}
+	raw, err := json.Marshal(objectMeta{ContentType: object.ContentType, Checksum: object.Checksum})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode metadata of %s: %w", object.Key, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary metadata for %s: %w", object.Key, err)
+	}
+	// CreateTemp makes 0600 files, sidecars keep the mode WriteFile gave them
+	if err = tmp.Chmod(0o644); err == nil {
+		_, err = tmp.Write(raw)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to write metadata of %s: %w", object.Key, err)
+	}
+	return tmp.Name(), nil
+}
+
+func (s *LocalStorage) objectPath(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(key))
+}
+
+func (s *LocalStorage) metaPath(key string) string {
+	return filepath.Join(s.root, metaDir, filepath.FromSlash(key)+".json")
+}
+
+// contextReader stops long copies once the request is gone
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
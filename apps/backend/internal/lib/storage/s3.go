@@ -0,0 +1,212 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+const (
+	// s3PartSize bounds the memory used for uploads of unknown size, minio would otherwise
+	// pick parts large enough for a 5 TiB object
+	s3PartSize = 16 << 20
+
+	// s3ChecksumMeta is the user metadata (x-amz-meta-checksum-sha256) holding the hex SHA-256 of the content
+	s3ChecksumMeta = "Checksum-Sha256"
+)
+
+type S3Options struct {
+	Endpoint        string // host[:port] of AWS S3 or any compatible service e.g. MinIO
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	UseSSL          bool
+}
+
+// S3Storage keeps objects in a bucket of an S3 compatible service. S3 only offers MD5 ETags for single
+// part uploads, the SHA-256 checksum is kept as user metadata. Objects stored by earlier versions have it
+// in a sidecar object .meta/<key>.json like LocalStorage does, those are still read and deleted.
+type S3Storage struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3Storage connects to the endpoint and creates the bucket when it doesn't exist yet
+func NewS3Storage(ctx context.Context, opts S3Options) (*S3Storage, error) {
+	if opts.Endpoint == "" || opts.Bucket == "" {
+		return nil, errors.New("storage: s3 endpoint and bucket are required")
+	}
+
+	client, err := minio.New(opts.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(opts.AccessKeyID, opts.SecretAccessKey, ""),
+		Secure: opts.UseSSL,
+		Region: opts.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 client: %w", err)
+	}
+
+	exists, err := client.BucketExists(ctx, opts.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bucket %s: %w", opts.Bucket, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, opts.Bucket, minio.MakeBucketOptions{Region: opts.Region}); err != nil {
+			return nil, fmt.Errorf("failed to create bucket %s: %w", opts.Bucket, err)
+		}
+	}
+
+	return &S3Storage{client: client, bucket: opts.Bucket}, nil
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, r io.Reader, opts PutOptions) (Object, error) {
+	key, err := objectKey(key)
+	if err != nil {
+		return Object{}, err
+	}
+
+	size := opts.Size
+	if size <= 0 {
+		size = -1
+	}
+
+	// the content goes to a temporary key below the reserved prefix first, key is only replaced by the copy
+	// at the end so a failed upload keeps the previous object
+	tmpKey := metaDir + "/.upload-" + uuid.NewString()
+	content := newChecksumReader(r)
+	if _, err := s.client.PutObject(ctx, s.bucket, tmpKey, content, size, minio.PutObjectOptions{
+		ContentType: opts.ContentType,
+		PartSize:    s3PartSize,
+	}); err != nil {
+		// minio aborts failed multipart uploads, no parts are left behind
+		return Object{}, fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	defer func() {
+		cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		_ = s.client.RemoveObject(cleanupCtx, s.bucket, tmpKey, minio.RemoveObjectOptions{})
+	}()
+
+	// the checksum is only known once the content went through, the copy sets it as metadata so the
+	// object and its checksum replace the old ones in one step
+	checksum := content.Checksum()
+	metadata := map[string]string{s3ChecksumMeta: checksum}
+	if opts.ContentType != "" {
+		metadata["Content-Type"] = opts.ContentType
+	}
+	info, err := s.client.ComposeObject(ctx,
+		minio.CopyDestOptions{Bucket: s.bucket, Object: key, UserMetadata: metadata, ReplaceMetadata: true},
+		minio.CopySrcOptions{Bucket: s.bucket, Object: tmpKey})
+	if err != nil {
+		return Object{}, fmt.Errorf("failed to store %s: %w", key, err)
+	}
+
+	return Object{
+		Key:         key,
+		Size:        content.size,
+		ContentType: opts.ContentType,
+		Checksum:    checksum,
+		ModTime:     info.LastModified.UTC(),
+	}, nil
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadSeekCloser, Object, error) {
+	key, err := objectKey(key)
+	if err != nil {
+		return nil, Object{}, err
+	}
+
+	reader, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, Object{}, s.mapError(key, err)
+	}
+	// GetObject is lazy, Stat issues the request so a missing key fails here and not on the first read
+	info, err := reader.Stat()
+	if err != nil {
+		reader.Close()
+		return nil, Object{}, s.mapError(key, err)
+	}
+	return reader, s.objectFromInfo(ctx, key, info), nil
+}
+
+func (s *S3Storage) Stat(ctx context.Context, key string) (Object, error) {
+	key, err := objectKey(key)
+	if err != nil {
+		return Object{}, err
+	}
+
+	info, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return Object{}, s.mapError(key, err)
+	}
+	return s.objectFromInfo(ctx, key, info), nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	key, err := objectKey(key)
+	if err != nil {
+		return err
+	}
+
+	// S3 reports success for missing keys already
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	if err := s.client.RemoveObject(ctx, s.bucket, metaKey(key), minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete metadata of %s: %w", key, err)
+	}
+	return nil
+}
+
+// readMeta returns the sidecar objects stored before the checksum moved into their metadata had,
+// objects without one (or with one from a replaced object) have no checksum
+func (s *S3Storage) readMeta(ctx context.Context, key string, etag string) (objectMeta, bool) {
+	reader, err := s.client.GetObject(ctx, s.bucket, metaKey(key), minio.GetObjectOptions{})
+	if err != nil {
+		return objectMeta{}, false
+	}
+	defer reader.Close()
+
+	var meta objectMeta
+	if err := json.NewDecoder(io.LimitReader(reader, 4<<10)).Decode(&meta); err != nil || meta.ETag != etag {
+		return objectMeta{}, false
+	}
+	return meta, true
+}
+
+func (s *S3Storage) mapError(key string, err error) error {
+	response := minio.ToErrorResponse(err)
+	if response.StatusCode == http.StatusNotFound || response.Code == "NoSuchKey" {
+		return fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return fmt.Errorf("failed to read %s: %w", key, err)
+}
+
+func (s *S3Storage) objectFromInfo(ctx context.Context, key string, info minio.ObjectInfo) Object {
+	object := Object{
+		Key:         key,
+		Size:        info.Size,
+		ContentType: info.ContentType,
+		ModTime:     info.LastModified.UTC(),
+	}
+	if checksum := info.UserMetadata[s3ChecksumMeta]; checksum != "" {
+		object.Checksum = checksum
+	} else if meta, ok := s.readMeta(ctx, key, info.ETag); ok {
+		object.Checksum = meta.Checksum
+	}
+	return object
+}
+
+// metaKey is where the sidecar of key lives, objectKey keeps clients out of this prefix
+func metaKey(key string) string {
+	return metaDir + "/" + key + ".json"
+}
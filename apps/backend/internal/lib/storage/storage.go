@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Mayank85Y/boil/internal/config"
+)
+
+var (
+	// ErrNotFound is returned when the key holds no object
+	ErrNotFound = errors.New("storage: object not found")
+
+	// ErrInvalidKey is returned for keys that are empty, absolute, try to escape with ".." or point into
+	// the reserved .meta/ prefix
+	ErrInvalidKey = errors.New("storage: invalid key")
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// metaDir keeps the sidecar metadata next to the objects, keys below it are reserved
+const metaDir = ".meta"
+
+// Object describes a stored object
+type Object struct {
+	Key         string    `json:"key"`
+	Size        int64     `json:"size"`
+	ContentType string    `json:"contentType"`
+	Checksum    string    `json:"checksum"` // hex encoded SHA-256 of the content
+	ModTime     time.Time `json:"modTime"`
+}
+
+// PutOptions describe the content passed to Put
+type PutOptions struct {
+	ContentType string
+	// Size lets backends skip buffering, -1 (or 0 with content) means unknown
+	Size int64
+}
+
+// Storage keeps objects addressed by slash separated keys e.g. uploads/2026/avatar.png
+type Storage interface {
+	// Put streams r into key, replacing what was there. The checksum is computed while writing.
+	// A failed Put leaves nothing half written behind, callers don't clean up after it.
+	Put(ctx context.Context, key string, r io.Reader, opts PutOptions) (Object, error)
+	// Get opens key for reading, the reader seeks so it can be served with Range support
+	Get(ctx context.Context, key string) (io.ReadSeekCloser, Object, error)
+	// Stat returns what Put stored without opening the content
+	Stat(ctx context.Context, key string) (Object, error)
+	// Delete removes key, deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// New builds the driver selected in the config
+func New(ctx context.Context, cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "", DriverLocal:
+		return NewLocalStorage(cfg.LocalPath)
+	case DriverS3:
+		return NewS3Storage(ctx, S3Options{
+			Endpoint:        cfg.S3.Endpoint,
+			Region:          cfg.S3.Region,
+			Bucket:          cfg.S3.Bucket,
+			AccessKeyID:     cfg.S3.AccessKeyID,
+			SecretAccessKey: cfg.S3.SecretAccessKey,
+			UseSSL:          cfg.S3.UseSSL,
+		})
+	default:
+		return nil, fmt.Errorf("storage: unknown driver %q", cfg.Driver)
+	}
+}
+
+// NewLazy checks the config but builds the driver on first use, servers without upload routes then neither
+// need the bucket reachable nor create the local directory. A failed build is retried by the next call.
+func NewLazy(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "", DriverLocal, DriverS3:
+		return &lazyStorage{cfg: cfg}, nil
+	default:
+		return nil, fmt.Errorf("storage: unknown driver %q", cfg.Driver)
+	}
+}
+
+type lazyStorage struct {
+	cfg   config.StorageConfig
+	mu    sync.Mutex
+	store Storage
+}
+
+func (l *lazyStorage) get(ctx context.Context) (Storage, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.store == nil {
+		store, err := New(ctx, l.cfg)
+		if err != nil {
+			return nil, err
+		}
+		l.store = store
+	}
+	return l.store, nil
+}
+
+func (l *lazyStorage) Put(ctx context.Context, key string, r io.Reader, opts PutOptions) (Object, error) {
+	store, err := l.get(ctx)
+	if err != nil {
+		return Object{}, err
+	}
+	return store.Put(ctx, key, r, opts)
+}
+
+func (l *lazyStorage) Get(ctx context.Context, key string) (io.ReadSeekCloser, Object, error) {
+	store, err := l.get(ctx)
+	if err != nil {
+		return nil, Object{}, err
+	}
+	return store.Get(ctx, key)
+}
+
+func (l *lazyStorage) Stat(ctx context.Context, key string) (Object, error) {
+	store, err := l.get(ctx)
+	if err != nil {
+		return Object{}, err
+	}
+	return store.Stat(ctx, key)
+}
+
+func (l *lazyStorage) Delete(ctx context.Context, key string) error {
+	store, err := l.get(ctx)
+	if err != nil {
+		return err
+	}
+	return store.Delete(ctx, key)
+}
+
+// CleanKey normalises key and rejects keys that would leave the storage root
+func CleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+
+	cleaned := path.Clean(key)
+	if cleaned == "." {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return cleaned, nil
+}
+
+// objectKey cleans key for use with a backend and rejects the reserved metadata prefix
+func objectKey(key string) (string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	if key == metaDir || strings.HasPrefix(key, metaDir+"/") {
+		return "", fmt.Errorf("%w: %q is reserved", ErrInvalidKey, key)
+	}
+	return key, nil
+}
+
+// objectMeta is the sidecar stored for every object
+type objectMeta struct {
+	ContentType string `json:"contentType,omitempty"`
+	Checksum    string `json:"checksum"`
+	// ETag of the S3 object the checksum belongs to, a sidecar left over from a replaced object is ignored
+	ETag string `json:"etag,omitempty"`
+}
+
+// checksumReader hashes and counts what passes through it
+type checksumReader struct {
+	r    io.Reader
+	hash hash.Hash
+	size int64
+}
+
+func newChecksumReader(r io.Reader) *checksumReader {
+	return &checksumReader{r: r, hash: sha256.New()}
+}
+
+func (c *checksumReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.hash.Write(p[:n])
+	c.size += int64(n)
+	return n, err
+}
+
+func (c *checksumReader) Checksum() string {
+	return hex.EncodeToString(c.hash.Sum(nil))
+}
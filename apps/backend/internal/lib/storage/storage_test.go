@@ -0,0 +1,83 @@
+package storage_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Mayank85Y/boil/internal/config"
+	"github.com/Mayank85Y/boil/internal/lib/storage"
+	testhelpers "github.com/Mayank85Y/boil/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage(t *testing.T) {
+	testhelpers.RunStorageSuite(t, testhelpers.NewTestLocalStorage(t))
+}
+
+func TestS3Storage(t *testing.T) {
+	testhelpers.RunStorageSuite(t, testhelpers.SetupTestS3(t))
+}
+
+func TestLazyStorage(t *testing.T) {
+	ctx := context.Background()
+	root := filepath.Join(t.TempDir(), "uploads")
+
+	store, err := storage.NewLazy(config.StorageConfig{Driver: storage.DriverLocal, LocalPath: root})
+	require.NoError(t, err)
+	assert.NoDirExists(t, root, "nothing is created before the first use")
+
+	_, err = store.Stat(ctx, "avatar.png")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.DirExists(t, root)
+
+	testhelpers.RunStorageSuite(t, store)
+}
+
+func TestLazyStorageErrors(t *testing.T) {
+	_, err := storage.NewLazy(config.StorageConfig{Driver: "ftp"})
+	assert.Error(t, err, "unknown drivers still fail at startup")
+
+	// the driver is only built when used, its errors surface there
+	store, err := storage.NewLazy(config.StorageConfig{Driver: storage.DriverLocal})
+	require.NoError(t, err)
+	_, err = store.Put(context.Background(), "a.txt", strings.NewReader("a"), storage.PutOptions{})
+	assert.ErrorContains(t, err, "local path is required")
+}
+
+// the sidecar is written before anything is replaced, when that fails the old object must survive
+func TestLocalStorageKeepsObjectWhenMetadataFails(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store, err := storage.NewLocalStorage(root)
+	require.NoError(t, err)
+
+	first, err := store.Put(ctx, "docs/a.txt", strings.NewReader("first"), storage.PutOptions{})
+	require.NoError(t, err)
+
+	// a directory where the sidecar goes makes renaming the new one into place fail
+	metaPath := filepath.Join(root, ".meta", "docs", "a.txt.json")
+	require.NoError(t, os.Remove(metaPath))
+	require.NoError(t, os.MkdirAll(filepath.Join(metaPath, "blocked"), 0o755))
+
+	_, err = store.Put(ctx, "docs/a.txt", strings.NewReader("second"), storage.PutOptions{})
+	require.Error(t, err)
+
+	content, err := os.ReadFile(filepath.Join(root, "docs", "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(content))
+
+	stat, err := store.Stat(ctx, "docs/a.txt")
+	require.NoError(t, err)
+	assert.Equal(t, first.Size, stat.Size)
+
+	leftovers, err := filepath.Glob(filepath.Join(root, "docs", ".upload-*"))
+	require.NoError(t, err)
+	assert.Empty(t, leftovers)
+	leftovers, err = filepath.Glob(filepath.Join(root, ".meta", "docs", ".upload-*"))
+	require.NoError(t, err)
+	assert.Empty(t, leftovers)
+}
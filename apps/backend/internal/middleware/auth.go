@@ -3,6 +3,7 @@ package middleware
 import (
 	"encoding/json"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/clerk/clerk-sdk-go/v2"
@@ -31,12 +32,7 @@ func (auth *AuthMiddleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc
 				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusUnauthorized)
 
-				response := map[string]string{
-					"code":     "UNAUTHORIZED",
-					"message":  "Unauthorized",
-					"override": "false",
-					"status":   "401",
-				}
+				response := errs.NewUnauthorizedError("Unauthorized", false)
 
 				if err := json.NewEncoder(w).Encode(response); err != nil {
 					auth.server.Logger.Error().Err(err).Str("function", "RequireAuth").Dur(
@@ -60,7 +56,7 @@ func (auth *AuthMiddleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc
 
 		c.Set("user_id", claims.Subject)
 		c.Set("user_role", claims.ActiveOrganizationRole)
-		c.Set("permissions", claims.Claims.ActiveOrganizationPermissions)
+		c.Set(PermissionsKey, claims.Claims.ActiveOrganizationPermissions)
 
 		auth.server.Logger.Info().
 			Str("function", "RequireAuth").
@@ -71,4 +67,24 @@ func (auth *AuthMiddleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc
 
 		return next(c)
 	})
-}
\ No newline at end of file
+}
+// RequirePermissions rejects callers missing any of the permissions, it must run after RequireAuth
+func (auth *AuthMiddleware) RequirePermissions(permissions ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			granted := GetPermissions(c)
+			for _, permission := range permissions {
+				if !slices.Contains(granted, permission) {
+					auth.server.Logger.Warn().
+						Str("function", "RequirePermissions").
+						Str("user_id", GetUserID(c)).
+						Str("request_id", GetRequestID(c)).
+						Str("permission", permission).
+						Msg("missing permission")
+					return errs.NewForbiddenError("You don't have permission to perform this action", false)
+				}
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// DebugTokenHeader carries "<unix timestamp>.<hex hmac-sha256 of timestamp, method and path>" signed with
+	// auth.debug_secret. A token only unlocks the request it was signed for and can be replayed against that
+	// method and path until it is debugTokenMaxAge old, so sign one per request and keep the header out of logs.
+	DebugTokenHeader = "X-Debug-Token"
+
+	// DebugPermission lets authenticated users see error internals without a debug token
+	DebugPermission = "org:debug:errors"
+
+	PermissionsKey = "permissions"
+
+	debugTokenMaxAge = 5 * time.Minute
+)
+
+// SignDebugToken builds a debug token for one request (method and URL path without the query) at the
+// given time, used by tooling and tests
+func SignDebugToken(secret string, at time.Time, method string, path string) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return timestamp + "." + debugTokenSignature(secret, timestamp, method, path)
+}
+
+func debugTokenSignature(secret string, timestamp string, method string, path string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + strings.ToUpper(method) + "\n" + path))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyDebugToken checks the signature over method and path and that the token is at most debugTokenMaxAge old
+func verifyDebugToken(secret string, token string, method string, path string, now time.Time) bool {
+	if secret == "" || token == "" {
+		return false
+	}
+
+	timestamp, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	issuedAt := time.Unix(unix, 0)
+	if now.Sub(issuedAt) > debugTokenMaxAge || issuedAt.Sub(now) > time.Minute {
+		return false
+	}
+
+	expected := debugTokenSignature(secret, timestamp, method, path)
+	return hmac.Equal([]byte(signature), []byte(expected))
+}
+
+func GetPermissions(c echo.Context) []string {
+	if permissions, ok := c.Get(PermissionsKey).([]string); ok {
+		return permissions
+	}
+	return nil
+}
+
+// isTrustedDebugCaller reports whether the caller may see wrapped errors and stacks
+func (global *GlobalMiddlewares) isTrustedDebugCaller(c echo.Context) bool {
+	if slices.Contains(GetPermissions(c), DebugPermission) {
+		return true
+	}
+	req := c.Request()
+	return verifyDebugToken(global.server.Config.Auth.DebugSecret, req.Header.Get(DebugTokenHeader),
+		req.Method, req.URL.Path, time.Now())
+}
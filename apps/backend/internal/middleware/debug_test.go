@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugTokenIsBoundToRequest(t *testing.T) {
+	const secret = "debug-secret"
+	now := time.Unix(time.Now().Unix(), 0)
+	token := SignDebugToken(secret, now, http.MethodGet, "/api/v1/todos")
+
+	assert.True(t, verifyDebugToken(secret, token, http.MethodGet, "/api/v1/todos", now))
+	assert.True(t, verifyDebugToken(secret, token, http.MethodGet, "/api/v1/todos", now.Add(debugTokenMaxAge)))
+
+	assert.False(t, verifyDebugToken(secret, token, http.MethodDelete, "/api/v1/todos", now), "other method")
+	assert.False(t, verifyDebugToken(secret, token, http.MethodGet, "/api/v1/users", now), "other path")
+	assert.False(t, verifyDebugToken(secret, token, http.MethodGet, "/api/v1/todos", now.Add(debugTokenMaxAge+time.Second)), "expired")
+	assert.False(t, verifyDebugToken("other-secret", token, http.MethodGet, "/api/v1/todos", now), "other secret")
+	assert.False(t, verifyDebugToken("", token, http.MethodGet, "/api/v1/todos", now), "no secret configured")
+}
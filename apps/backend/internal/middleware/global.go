@@ -1,9 +1,13 @@
 package middleware
 
 import (
+	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/Mayank85Y/boil/internal/i18n"
 	"github.com/Mayank85Y/boil/internal/server"
 	"github.com/Mayank85Y/boil/internal/sqlerr"
 	"github.com/labstack/echo/v4"
@@ -124,6 +128,9 @@ var echoErr *echo.HTTPError
 	var message string
 	var fieldErrors []errs.FieldError
 	var action *errs.Action
+	var details map[string]any
+	var headers http.Header
+	var cause error
 
 	switch {
 	case errors.As(err, &httpErr):
@@ -132,10 +139,13 @@ var echoErr *echo.HTTPError
 		message = httpErr.Message
 		fieldErrors = httpErr.Errors
 		action = httpErr.Action
+		details = httpErr.Details
+		headers = httpErr.Headers
+		cause = httpErr.Cause
 
 	case errors.As(err, &echoErr):
 		status = echoErr.Code
-		code = errs.MakeUpperCaseWithUnderscores(http.StatusText(status))
+		code = errs.CodeForStatus(status)
 		if msg, ok := echoErr.Message.(string); ok {
 			message = msg
 		} else {
@@ -144,8 +154,7 @@ var echoErr *echo.HTTPError
 
 	default:
 		status = http.StatusInternalServerError
-		code = errs.MakeUpperCaseWithUnderscores(
-			http.StatusText(http.StatusInternalServerError))
+		code = errs.CodeInternalServerError
 		message = http.StatusText(http.StatusInternalServerError)
 	}
 
@@ -153,20 +162,108 @@ var echoErr *echo.HTTPError
 	// Use enhanced logger from context which already includes request_id, method, path, ip, user context, and trace context
 	logger := *GetLogger(c)
 
-	logger.Error().Stack().
+	event := logger.Error().Stack().
 		Err(originalErr).
 		Int("status", status).
-		Str("error_code", code).
-		Msg(message)
+		Str("error_code", code)
+	if cause != nil && cause != originalErr {
+		event = event.AnErr("cause", cause)
+	}
+	event.Msg(message)
+
+	// every code sent to clients must be declared in the errs catalog
+	if !errs.IsRegisteredCode(code) {
+		logger.Warn().
+			Str("error_code", code).
+			Msg("error code is not registered in the errs catalog")
+	}
+
+	// unexpected failures: give the user something to quote when contacting support
+	if status >= http.StatusInternalServerError && action == nil && (httpErr == nil || !httpErr.Override) {
+		action = errs.NewContactSupportAction("If the problem persists, contact support with this reference",
+			errs.ContactSupportPayload{Reference: GetRequestID(c)})
+	}
 
 	if !c.Response().Committed {
-		_ = c.JSON(status, errs.HTTPError{
+		// headers attached to the error, e.g. Retry-After
+		for key, values := range headers {
+			for _, value := range values {
+				c.Response().Header().Add(key, value)
+			}
+		}
+
+		response := &errs.HTTPError{
 			Code:     code,
 			Message:  message,
 			Status:   status,
 			Override: httpErr != nil && httpErr.Override,
 			Errors:   fieldErrors,
 			Action:   action,
-		})
+			Details:  details,
+		}
+		if httpErr != nil {
+			response.MessageKey = httpErr.MessageKey
+			response.MessageParams = httpErr.MessageParams
+		}
+
+		// trusted callers get the internals back, everyone else in production gets nothing internal
+		if global.isTrustedDebugCaller(c) {
+			response.Debug = errs.NewDebugInfo(originalErr)
+			c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+		} else if global.server.Config.Primary.IsProduction() {
+			response = response.Redact(GetRequestID(c))
+		}
+
+		// codes stay as they are, only the human readable text follows the caller's language
+		locale := i18n.FromEcho(c)
+		response = localizeError(response, locale)
+		c.Response().Header().Set(i18n.HeaderContentLanguage, locale.String())
+		c.Response().Header().Add(echo.HeaderVary, i18n.HeaderAcceptLanguage)
+
+		// RFC 7807 for clients that ask for it, our own shape stays the default
+		c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+		if wantsProblemJSON(c.Request().Header.Get(echo.HeaderAccept)) {
+			body, err := json.Marshal(response.ToProblem(GetRequestID(c)))
+			if err == nil {
+				_ = c.Blob(status, errs.MIMEApplicationProblemJSON, body)
+				return
+			}
+			logger.Error().Err(err).Msg("failed to marshal problem details")
+		}
+
+		_ = c.JSON(status, response)
+	}
+}
+
+// wantsProblemJSON reports whether the Accept header prefers application/problem+json
+// over application/json. Wildcards don't count so */* keeps the default format.
+func wantsProblemJSON(accept string) bool {
+	if accept == "" {
+		return false
+	}
+
+	problemQ, jsonQ := -1.0, -1.0
+	for _, mediaRange := range strings.Split(accept, ",") {
+		params := strings.Split(mediaRange, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+
+		q := 1.0
+		for _, param := range params[1:] {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.EqualFold(name, "q") {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		switch mediaType {
+		case errs.MIMEApplicationProblemJSON:
+			problemQ = max(problemQ, q)
+		case echo.MIMEApplicationJSON:
+			jsonQ = max(jsonQ, q)
+		}
 	}
+
+	return problemQ > 0 && problemQ >= jsonQ
 }
\ No newline at end of file
@@ -0,0 +1,77 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Mayank85Y/boil/internal/config"
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/Mayank85Y/boil/internal/middleware"
+	"github.com/Mayank85Y/boil/internal/server"
+	"github.com/Mayank85Y/boil/internal/sqlerr"
+	testhelpers "github.com/Mayank85Y/boil/internal/testing"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// every error a handler can return must reach the client with a code from the errs catalog
+func TestGlobalErrorHandlerEmitsRegisteredCodes(t *testing.T) {
+	logger := zerolog.Nop()
+	global := middleware.NewGlobalMiddlewares(&server.Server{
+		Config: &config.Config{Primary: config.Primary{Env: "test"}},
+		Logger: &logger,
+	})
+
+	fixtures := map[string]error{
+		"echo not found":          echo.ErrNotFound,
+		"echo method not allowed": echo.ErrMethodNotAllowed,
+		"echo too large":          echo.ErrStatusRequestEntityTooLarge,
+		"echo unsupported media":  echo.ErrUnsupportedMediaType,
+		"echo service down":       echo.NewHTTPError(http.StatusServiceUnavailable),
+		"plain error":             errors.New("boom"),
+		"validation":              errs.ValidationError(errors.New("name is required")),
+		"too many requests":       errs.NewTooManyRequestsError("slow down", true, 0),
+		"not found entity":        sqlerr.NewNotFoundError("gizmo", "id", 1, nil),
+		"not found no entity":     sqlerr.NewNotFoundError("", "id", 1, nil),
+		"not found bad entity":    sqlerr.NewNotFoundError("2fa code", "id", 1, nil),
+	}
+	for _, code := range []string{
+		errs.CodeIdempotencyKeyRequired, errs.CodeIdempotencyKeyInvalid,
+		errs.CodeIdempotencyKeyInUse, errs.CodeIdempotencyKeyReused,
+	} {
+		code := code
+		fixtures[code] = errs.NewBadRequestError(code, true, &code, nil, nil)
+	}
+	// every SQLSTATE sqlerr knows about, for a table, one that doesn't make a valid code and without one
+	for _, state := range []string{
+		"23502", "23503", "23505", "23514", "23P01", "23001", "25P02", "40P01", "40001", "53300",
+		"53100", "22001", "22P02", "22012", "55P03", "57014", "08001", "08006", "XX000",
+	} {
+		for _, table := range []string{"gizmos", "2fa_codes", ""} {
+			fixtures["sqlstate "+state+" "+table] = &pgconn.PgError{
+				Code:           state,
+				Message:        "fixture",
+				TableName:      table,
+				ColumnName:     "name",
+				ConstraintName: table + "_name_key",
+			}
+		}
+	}
+
+	e := echo.New()
+	for name, err := range fixtures {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+			global.GlobalErrorHandler(err, c)
+
+			assert.GreaterOrEqual(t, rec.Code, http.StatusBadRequest)
+			testhelpers.AssertRegisteredErrorResponse(t, rec.Body.Bytes())
+		})
+	}
+}
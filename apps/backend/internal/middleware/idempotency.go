@@ -0,0 +1,423 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/Mayank85Y/boil/internal/server"
+	"github.com/Mayank85Y/boil/internal/sqlerr"
+	"github.com/Mayank85Y/boil/internal/validation"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// DefaultIdempotencyTTL is how long completed responses are replayed when the policy sets no TTL
+	DefaultIdempotencyTTL = 24 * time.Hour
+
+	// DefaultIdempotencyLockTimeout frees keys of requests that never completed, e.g. after a crash
+	DefaultIdempotencyLockTimeout = time.Minute
+
+	maxIdempotencyKeyLength = 255
+
+	// responses larger than this are not stored, retries run the handler again
+	maxIdempotentResponseBytes = 1 << 20
+
+	idempotencyInFlightRetry = time.Second
+)
+
+// IdempotencyPolicy makes retries of unsafe requests carrying an Idempotency-Key header safe
+type IdempotencyPolicy struct {
+	// TTL keeps completed responses for replay, 0 means DefaultIdempotencyTTL
+	TTL time.Duration
+	// LockTimeout bounds how long a request may hold its key, 0 means DefaultIdempotencyLockTimeout.
+	// It should be longer than the route timeout.
+	LockTimeout time.Duration
+	// Required rejects requests without a key
+	Required bool
+}
+
+func (p IdempotencyPolicy) ttl() time.Duration {
+	if p.TTL <= 0 {
+		return DefaultIdempotencyTTL
+	}
+	return p.TTL
+}
+
+func (p IdempotencyPolicy) lockTimeout() time.Duration {
+	if p.LockTimeout <= 0 {
+		return DefaultIdempotencyLockTimeout
+	}
+	return p.LockTimeout
+}
+
+func (p IdempotencyPolicy) String() string {
+	s := p.ttl().String()
+	if p.Required {
+		s += " required"
+	}
+	return s
+}
+
+const (
+	idempotencyInFlight  = "in_flight"
+	idempotencyCompleted = "completed"
+)
+
+// idempotencyRecord is what server.Redis keeps per key
+type idempotencyRecord struct {
+	State       string      `json:"state"`
+	Owner       string      `json:"owner,omitempty"` // random per in-flight request, see releaseIdempotencyScript
+	Fingerprint string      `json:"fingerprint"`
+	Status      int         `json:"status,omitempty"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+}
+
+// replayed headers are the ones describing the stored body, the rest belongs to the original request
+var idempotentResponseHeaders = []string{
+	echo.HeaderContentType,
+	echo.HeaderContentDisposition,
+	echo.HeaderLocation,
+	"ETag",
+	echo.HeaderLastModified,
+}
+
+// release and store only touch the key while it still holds our in-flight record, a request that outlived
+// its LockTimeout must not delete or overwrite the record of the request that took the key over
+var (
+	releaseIdempotencyScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+	storeIdempotencyScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+	return 1
+end
+return 0`)
+)
+
+type IdempotencyMiddleware struct {
+	server *server.Server
+}
+
+func NewIdempotencyMiddleware(s *server.Server) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		server: s,
+	}
+}
+
+// Idempotent stores the response of the first request with a given Idempotency-Key and replays it to
+// retries. Keys are scoped to the caller (user, IP when unauthenticated) and the route, a retry with a
+// different body is rejected with 422 and one arriving while the first is still running gets 409.
+// Client errors are stored and replayed like any other response, a retry would only get them again.
+// Server errors, transient client errors (429, anything asking to retry) and broken responses free the key
+// so the request can be retried.
+func (m *IdempotencyMiddleware) Idempotent(policy IdempotencyPolicy) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			switch req.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				return next(c)
+			}
+
+			key := req.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				if policy.Required {
+					code := errs.CodeIdempotencyKeyRequired
+					return errs.NewBadRequestError("The Idempotency-Key header is required", true, &code, nil, nil).
+						WithMessageKey("idempotency.required", nil)
+				}
+				return next(c)
+			}
+			if !validIdempotencyKey(key) {
+				code := errs.CodeIdempotencyKeyInvalid
+				return errs.NewBadRequestError("The Idempotency-Key header is invalid", true, &code, nil, nil).
+					WithMessageKey("idempotency.invalid", map[string]string{"max": strconv.Itoa(maxIdempotencyKeyLength)})
+			}
+
+			fingerprint, err := fingerprintRequest(c)
+			if err != nil {
+				return err
+			}
+
+			logger := GetLogger(c).With().Str("idempotency_key", key).Logger()
+			ctx := req.Context()
+			redisKey := m.redisKey(c, key)
+
+			record, inFlight, err := m.acquire(ctx, redisKey, fingerprint, policy.lockTimeout())
+			if err != nil {
+				logger.Error().Err(err).Msg("idempotency store unavailable")
+				return errs.NewServiceUnavailableError("The request could not be processed, please retry", true, nil, idempotencyInFlightRetry)
+			}
+
+			if inFlight == nil {
+				switch {
+				case record.Fingerprint != fingerprint:
+					code := errs.CodeIdempotencyKeyReused
+					return errs.NewUnprocessableEntityError(
+						"The Idempotency-Key was already used for a different request", true, &code, nil).
+						WithMessageKey("idempotency.reused", nil)
+
+				case record.State != idempotencyCompleted:
+					code := errs.CodeIdempotencyKeyInUse
+					return errs.NewConflictError("A request with this Idempotency-Key is still in progress", true, &code).
+						WithMessageKey("idempotency.in_use", nil).
+						WithRetry(idempotencyInFlightRetry)
+				}
+
+				logger.Debug().Int("status", record.Status).Msg("replaying idempotent response")
+				return replayIdempotent(c, record)
+			}
+
+			recorder := &idempotencyRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+			err = next(c)
+			if err != nil && storableError(err) {
+				// render the error while the recorder is still in place so its response can be stored
+				c.Error(err)
+				err = nil
+			}
+			c.Response().Writer = recorder.ResponseWriter
+
+			// the request is gone by now, the bookkeeping must still happen
+			storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+			defer cancel()
+
+			status := c.Response().Status
+			if err != nil || status >= http.StatusInternalServerError || recorder.overflow || !c.Response().Committed {
+				if released, releaseErr := m.release(storeCtx, redisKey, inFlight); releaseErr != nil {
+					logger.Error().Err(releaseErr).Msg("failed to release idempotency key")
+				} else if !released {
+					logger.Warn().Msg("idempotency key expired before the request finished")
+				}
+				return err
+			}
+
+			completed := idempotencyRecord{
+				State:       idempotencyCompleted,
+				Fingerprint: fingerprint,
+				Status:      status,
+				Header:      http.Header{},
+				Body:        recorder.body.Bytes(),
+			}
+			for _, name := range idempotentResponseHeaders {
+				if values := c.Response().Header().Values(name); len(values) > 0 {
+					completed.Header[name] = values
+				}
+			}
+			if stored, err := m.store(storeCtx, redisKey, inFlight, completed, policy.ttl()); err != nil {
+				// the response already went out, a retry will run the handler again once the lock expires
+				logger.Error().Err(err).Msg("failed to store idempotent response")
+			} else if !stored {
+				logger.Warn().Msg("idempotency key expired before the request finished, response not stored")
+			}
+			return nil
+		}
+	}
+}
+
+// acquire marks the key as in flight and returns the value written, release and store need it to prove
+// ownership. When someone else holds the key their record is returned instead and inFlight is nil.
+func (m *IdempotencyMiddleware) acquire(ctx context.Context, redisKey string, fingerprint string, lockTimeout time.Duration) (idempotencyRecord, []byte, error) {
+	if m.server.Redis == nil {
+		return idempotencyRecord{}, nil, errors.New("redis is not configured")
+	}
+
+	inFlight, err := json.Marshal(idempotencyRecord{
+		State:       idempotencyInFlight,
+		Owner:       uuid.NewString(),
+		Fingerprint: fingerprint,
+	})
+	if err != nil {
+		return idempotencyRecord{}, nil, err
+	}
+
+	// the holder may complete or expire between SET NX and GET, try again once
+	for attempt := 0; attempt < 2; attempt++ {
+		acquired, err := m.server.Redis.SetNX(ctx, redisKey, inFlight, lockTimeout).Result()
+		if err != nil {
+			return idempotencyRecord{}, nil, fmt.Errorf("failed to acquire idempotency key: %w", err)
+		}
+		if acquired {
+			return idempotencyRecord{}, inFlight, nil
+		}
+
+		raw, err := m.server.Redis.Get(ctx, redisKey).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return idempotencyRecord{}, nil, fmt.Errorf("failed to read idempotency key: %w", err)
+		}
+
+		var record idempotencyRecord
+		if err := json.Unmarshal(raw, &record); err != nil {
+			return idempotencyRecord{}, nil, fmt.Errorf("failed to decode idempotency record: %w", err)
+		}
+		return record, nil, nil
+	}
+	return idempotencyRecord{}, nil, errors.New("idempotency key changed hands while acquiring it")
+}
+
+// release frees the key if it still holds inFlight, false means the lock expired and someone else may own it
+func (m *IdempotencyMiddleware) release(ctx context.Context, redisKey string, inFlight []byte) (bool, error) {
+	res, err := releaseIdempotencyScript.Run(ctx, m.server.Redis, []string{redisKey}, inFlight).Int64()
+	if err != nil {
+		return false, err
+	}
+	return res == 1, nil
+}
+
+// store replaces inFlight with the completed record, false means the lock expired and nothing was stored
+func (m *IdempotencyMiddleware) store(ctx context.Context, redisKey string, inFlight []byte, record idempotencyRecord, ttl time.Duration) (bool, error) {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return false, err
+	}
+	res, err := storeIdempotencyScript.Run(ctx, m.server.Redis, []string{redisKey}, inFlight, raw, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return false, err
+	}
+	return res == 1, nil
+}
+
+// redisKey scopes the key like the rate limiter, callers can't replay each other's responses
+func (m *IdempotencyMiddleware) redisKey(c echo.Context, key string) string {
+	scope := "ip:" + c.RealIP()
+	if userID := GetUserID(c); userID != "" {
+		scope = "user:" + userID
+	}
+	return "idempotency:" + scope + ":" + c.Request().Method + ":" + c.Path() + ":" + key
+}
+
+// fingerprintRequest hashes what makes two requests the same: method, URL and body. The body is read
+// up to the route body limit and put back, larger bodies are left for binding to reject.
+func fingerprintRequest(c echo.Context) (string, error) {
+	req := c.Request()
+
+	limit, ok := c.Get(validation.MaxBodyBytesKey).(int64)
+	if !ok {
+		limit = validation.DefaultMaxBodyBytes
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(io.LimitReader(req.Body, limit+1))
+		if err != nil {
+			return "", errs.NewBadRequestError("Request body could not be read", true, nil, nil, nil).
+				WithMessageKey("bind.unreadable", nil).
+				WithCause(err)
+		}
+		req.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), req.Body), Closer: req.Body}
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", req.Method, req.URL.RequestURI())
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func replayIdempotent(c echo.Context, record idempotencyRecord) error {
+	header := c.Response().Header()
+	for name, values := range record.Header {
+		header[name] = values
+	}
+	header.Set(IdempotentReplayedHeader, "true")
+
+	c.Response().WriteHeader(record.Status)
+	if len(record.Body) == 0 {
+		return nil
+	}
+	_, err := c.Response().Write(record.Body)
+	return err
+}
+
+// storableError reports client errors a retry would get again. Errors that aren't HTTP errors yet are
+// converted like GlobalErrorHandler does, database constraint violations are deterministic too.
+func storableError(err error) bool {
+	var httpErr *errs.HTTPError
+	if !errors.As(err, &httpErr) {
+		var echoErr *echo.HTTPError
+		if errors.As(err, &echoErr) || !errors.As(sqlerr.HandleError(err), &httpErr) {
+			return false
+		}
+	}
+
+	switch {
+	case httpErr.Status < http.StatusBadRequest || httpErr.Status >= http.StatusInternalServerError:
+		return false
+	case httpErr.Status == http.StatusRequestTimeout || httpErr.Status == http.StatusTooManyRequests:
+		return false
+	case httpErr.Action != nil && httpErr.Action.Type == errs.ActionTypeRetry:
+		return false
+	case httpErr.Headers.Get(echo.HeaderRetryAfter) != "":
+		return false
+	}
+	return true
+}
+
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// idempotencyRecorder keeps a copy of the response body while it is written
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (r *idempotencyRecorder) Write(p []byte) (int, error) {
+	if !r.overflow {
+		if r.body.Len()+len(p) > maxIdempotentResponseBytes {
+			r.overflow = true
+			r.body.Reset()
+		} else {
+			r.body.Write(p)
+		}
+	}
+	return r.ResponseWriter.Write(p)
+}
+
+func (r *idempotencyRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *idempotencyRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,198 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Mayank85Y/boil/internal/config"
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/Mayank85Y/boil/internal/middleware"
+	"github.com/Mayank85Y/boil/internal/server"
+	testhelpers "github.com/Mayank85Y/boil/internal/testing"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIdempotencyEcho(t *testing.T, client *redis.Client, handler echo.HandlerFunc) *echo.Echo {
+	t.Helper()
+
+	logger := zerolog.Nop()
+	s := &server.Server{Config: &config.Config{}, Logger: &logger, Redis: client}
+
+	e := echo.New()
+	e.HTTPErrorHandler = middleware.NewGlobalMiddlewares(s).GlobalErrorHandler
+	e.POST("/orders", handler, middleware.NewIdempotencyMiddleware(s).Idempotent(middleware.IdempotencyPolicy{}))
+	return e
+}
+
+func postIdempotent(e *echo.Echo, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body errs.HTTPError
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body.Code
+}
+
+func TestIdempotency(t *testing.T) {
+	client := testhelpers.SetupTestRedis(t)
+
+	t.Run("replays the first response", func(t *testing.T) {
+		var runs atomic.Int32
+		e := newIdempotencyEcho(t, client, func(c echo.Context) error {
+			n := runs.Add(1)
+			c.Response().Header().Set(echo.HeaderLocation, "/orders/1")
+			return c.JSON(http.StatusCreated, map[string]int32{"run": n})
+		})
+		key := uuid.NewString()
+
+		first := postIdempotent(e, key, `{"item":"a"}`)
+		require.Equal(t, http.StatusCreated, first.Code)
+		assert.Empty(t, first.Header().Get(middleware.IdempotentReplayedHeader))
+
+		retry := postIdempotent(e, key, `{"item":"a"}`)
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, "true", retry.Header().Get(middleware.IdempotentReplayedHeader))
+		assert.Equal(t, "/orders/1", retry.Header().Get(echo.HeaderLocation))
+		assert.JSONEq(t, first.Body.String(), retry.Body.String())
+		assert.Equal(t, int32(1), runs.Load(), "the handler runs once")
+	})
+
+	t.Run("different body is rejected", func(t *testing.T) {
+		e := newIdempotencyEcho(t, client, func(c echo.Context) error {
+			return c.NoContent(http.StatusCreated)
+		})
+		key := uuid.NewString()
+
+		require.Equal(t, http.StatusCreated, postIdempotent(e, key, `{"item":"a"}`).Code)
+
+		rec := postIdempotent(e, key, `{"item":"b"}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, errs.CodeIdempotencyKeyReused, errorCode(t, rec))
+	})
+
+	t.Run("retry while in flight conflicts", func(t *testing.T) {
+		key := uuid.NewString()
+		var e *echo.Echo
+		var inner *httptest.ResponseRecorder
+		e = newIdempotencyEcho(t, client, func(c echo.Context) error {
+			if inner == nil {
+				inner = postIdempotent(e, key, `{"item":"a"}`)
+			}
+			return c.NoContent(http.StatusCreated)
+		})
+
+		require.Equal(t, http.StatusCreated, postIdempotent(e, key, `{"item":"a"}`).Code)
+		require.NotNil(t, inner)
+		assert.Equal(t, http.StatusConflict, inner.Code)
+		assert.Equal(t, errs.CodeIdempotencyKeyInUse, errorCode(t, inner))
+		assert.NotEmpty(t, inner.Header().Get("Retry-After"))
+	})
+
+	t.Run("errors free the key", func(t *testing.T) {
+		var runs atomic.Int32
+		e := newIdempotencyEcho(t, client, func(c echo.Context) error {
+			if runs.Add(1) == 1 {
+				return errs.NewInternalServerError()
+			}
+			return c.NoContent(http.StatusCreated)
+		})
+		key := uuid.NewString()
+
+		assert.Equal(t, http.StatusInternalServerError, postIdempotent(e, key, `{}`).Code)
+		assert.Equal(t, http.StatusCreated, postIdempotent(e, key, `{}`).Code)
+		assert.Equal(t, int32(2), runs.Load())
+	})
+
+	t.Run("client errors are replayed", func(t *testing.T) {
+		var runs atomic.Int32
+		e := newIdempotencyEcho(t, client, func(c echo.Context) error {
+			if runs.Add(1) == 1 {
+				return errs.NewBadRequestError("The item is sold out", true, nil, nil, nil)
+			}
+			return c.NoContent(http.StatusCreated)
+		})
+		key := uuid.NewString()
+
+		first := postIdempotent(e, key, `{}`)
+		require.Equal(t, http.StatusBadRequest, first.Code)
+
+		retry := postIdempotent(e, key, `{}`)
+		assert.Equal(t, http.StatusBadRequest, retry.Code)
+		assert.Equal(t, "true", retry.Header().Get(middleware.IdempotentReplayedHeader))
+		assert.Contains(t, retry.Body.String(), "The item is sold out")
+		assert.JSONEq(t, first.Body.String(), retry.Body.String())
+		assert.Equal(t, int32(1), runs.Load(), "the handler runs once")
+	})
+
+	t.Run("transient and framework errors free the key", func(t *testing.T) {
+		for name, transient := range map[string]error{
+			"too many requests": errs.NewTooManyRequestsError("Slow down", true, time.Second),
+			"retry action":      errs.NewConflictError("Busy", true, nil).WithRetry(time.Second),
+			"echo error":        echo.NewHTTPError(http.StatusBadRequest, "bad"),
+		} {
+			t.Run(name, func(t *testing.T) {
+				var runs atomic.Int32
+				e := newIdempotencyEcho(t, client, func(c echo.Context) error {
+					if runs.Add(1) == 1 {
+						return transient
+					}
+					return c.NoContent(http.StatusCreated)
+				})
+				key := uuid.NewString()
+
+				assert.GreaterOrEqual(t, postIdempotent(e, key, `{}`).Code, http.StatusBadRequest)
+				assert.Equal(t, http.StatusCreated, postIdempotent(e, key, `{}`).Code)
+				assert.Equal(t, int32(2), runs.Load())
+			})
+		}
+	})
+
+	// the lock expired mid request and another request took the key, finishing must leave its record alone
+	t.Run("expired holder does not touch the new owner", func(t *testing.T) {
+		ctx := context.Background()
+		const taken = `{"state":"in_flight","owner":"someone-else","fingerprint":"other"}`
+
+		for name, respond := range map[string]echo.HandlerFunc{
+			"store":   func(c echo.Context) error { return c.NoContent(http.StatusCreated) },
+			"release": func(c echo.Context) error { return errs.NewInternalServerError() },
+		} {
+			t.Run(name, func(t *testing.T) {
+				key := uuid.NewString()
+				e := newIdempotencyEcho(t, client, func(c echo.Context) error {
+					keys, err := client.Keys(ctx, "idempotency:*:"+key).Result()
+					require.NoError(t, err)
+					require.Len(t, keys, 1)
+					require.NoError(t, client.Set(ctx, keys[0], taken, 0).Err())
+					return respond(c)
+				})
+
+				postIdempotent(e, key, `{}`)
+
+				keys, err := client.Keys(ctx, "idempotency:*:"+key).Result()
+				require.NoError(t, err)
+				require.Len(t, keys, 1)
+				raw, err := client.Get(ctx, keys[0]).Result()
+				require.NoError(t, err)
+				assert.JSONEq(t, taken, raw)
+			})
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/Mayank85Y/boil/internal/validation"
+	"github.com/labstack/echo/v4"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
+)
+
+// Timeout cancels the request context after d. Handlers have to pass c.Request().Context() down
+// for the deadline to take effect, errors caused by it become 503.
+func Timeout(d time.Duration) echo.MiddlewareFunc {
+	return echoMiddleware.ContextTimeoutWithConfig(echoMiddleware.ContextTimeoutConfig{
+		Timeout: d,
+		ErrorHandler: func(err error, c echo.Context) error {
+			var httpErr *errs.HTTPError
+			if errors.As(err, &httpErr) || !errors.Is(err, context.DeadlineExceeded) {
+				return err
+			}
+			return errs.NewServiceUnavailableError("The request took too long", false, nil, 0)
+		},
+	})
+}
+
+// StrictBind makes binding reject unknown JSON fields and trailing data in the request body
+func StrictBind(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		c.Set(validation.StrictBindKey, true)
+		return next(c)
+	}
+}
+
+// BodyLimit replaces validation.DefaultMaxBodyBytes for the route. Bodies announcing a larger
+// Content-Length are rejected before anything is read, the rest is enforced while binding.
+func BodyLimit(limit int64) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().ContentLength > limit {
+				size := strconv.FormatInt(limit, 10)
+				return errs.NewRequestTooLargeError(
+					fmt.Sprintf("Request body must not exceed %s bytes", size), true, limit).
+					WithMessageKey("bind.too_large", map[string]string{"limit": size})
+			}
+			c.Set(validation.MaxBodyBytesKey, limit)
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/Mayank85Y/boil/internal/i18n"
+	"golang.org/x/text/language"
+)
+
+// localizeError translates the message and field errors of a response. Errors with a message key
+// are translated by key, other errors fall back to a generic text for their code unless the message
+// was written for users (Override). Anything without a translation stays English.
+func localizeError(e *errs.HTTPError, locale language.Tag) *errs.HTTPError {
+	if locale == i18n.Default {
+		return e
+	}
+
+	cp := *e
+	if message, ok := i18n.Lookup(locale, e.MessageKey, e.MessageParams); ok {
+		cp.Message = message
+	} else if e.MessageKey == "" && !e.Override {
+		if message, ok := lookupCodeMessage(locale, e.Code); ok {
+			cp.Message = message
+		}
+	}
+
+	if len(e.Errors) > 0 {
+		cp.Errors = make([]errs.FieldError, len(e.Errors))
+		for i, fe := range e.Errors {
+			if message, ok := i18n.Lookup(locale, fe.Key, fe.Params); ok {
+				fe.Error = message
+			}
+			cp.Errors[i] = fe
+		}
+	}
+	return &cp
+}
+
+// lookupCodeMessage translates by code, codes from an {ENTITY} family get the entity as {entity}
+func lookupCodeMessage(locale language.Tag, code string) (string, bool) {
+	if message, ok := i18n.Lookup(locale, code, nil); ok {
+		return message, true
+	}
+
+	info, ok := errs.LookupCode(code)
+	if !ok || !info.IsPattern() {
+		return "", false
+	}
+	prefix, suffix, _ := strings.Cut(info.Code, errs.EntityPlaceholder)
+	entity := strings.TrimSuffix(strings.TrimPrefix(code, prefix), suffix)
+	entity = strings.ToLower(strings.ReplaceAll(entity, "_", " "))
+	return i18n.Lookup(locale, info.Code, map[string]string{"entity": entity})
+}
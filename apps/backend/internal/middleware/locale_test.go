@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/Mayank85Y/boil/internal/i18n"
+	"github.com/Mayank85Y/boil/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+type localizedPayload struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// the top level message has to follow the locale just like the field errors do
+func TestLocalizeValidationError(t *testing.T) {
+	for locale, want := range map[language.Tag][2]string{
+		language.German:  {"Die Validierung ist fehlgeschlagen", "ist erforderlich"},
+		language.Spanish: {"La validación ha fallado", "es obligatorio"},
+	} {
+		// BindAndValidate puts the request locale into the context
+		err := validation.Validate(i18n.WithLocale(context.Background(), locale), &localizedPayload{})
+
+		var httpErr *errs.HTTPError
+		require.True(t, errors.As(err, &httpErr))
+
+		localized := localizeError(httpErr, locale)
+		assert.Equal(t, want[0], localized.Message, locale)
+		require.Len(t, localized.Errors, 1)
+		assert.Equal(t, want[1], localized.Errors[0].Error, locale)
+	}
+}
@@ -11,6 +11,7 @@ type Middlewares struct {
 	ContextEnhancer *ContextEnhancer
 	Tracing         *TracingMiddleware
 	RateLimit       *RateLimitMiddleware
+	Idempotency     *IdempotencyMiddleware
 }
 
 func NewMiddlewares(s *server.Server) *Middlewares{
@@ -25,5 +26,6 @@ func NewMiddlewares(s *server.Server) *Middlewares{
 			ContextEnhancer: 	NewContextEnhancer(s),
 			Tracing:			NewTracingMiddleware(s, nrApp),
 			RateLimit:			NewRateLimitMiddleware(s),
+			Idempotency:		NewIdempotencyMiddleware(s),
 		}
 }
\ No newline at end of file
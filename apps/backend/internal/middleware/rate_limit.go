@@ -1,14 +1,82 @@
 package middleware
 
-import "github.com/Mayank85Y/boil/internal/server"
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/Mayank85Y/boil/internal/server"
+	"github.com/labstack/echo/v4"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
+	"golang.org/x/time/rate"
+)
+
+// RateLimitPolicy allows Requests per Per on average with bursts of up to Burst requests,
+// Burst defaults to Requests. Routes using the same Name share one budget.
+type RateLimitPolicy struct {
+	Name     string
+	Requests int
+	Per      time.Duration
+	Burst    int
+}
+
+// DefaultRateLimit applies to every request, routes can add a stricter policy on top
+var DefaultRateLimit = RateLimitPolicy{Name: "default", Requests: 20, Per: time.Second}
+
+// Validate rejects policies that can't be turned into a rate
+func (p RateLimitPolicy) Validate() error {
+	switch {
+	case p.Requests <= 0:
+		return fmt.Errorf("rate limit policy %s: requests must be greater than 0", p)
+	case p.Per <= 0:
+		return fmt.Errorf("rate limit policy %s: per must be greater than 0", p)
+	case p.Burst < 0:
+		return fmt.Errorf("rate limit policy %s: burst must not be negative", p)
+	}
+	return nil
+}
+
+func (p RateLimitPolicy) limit() rate.Limit {
+	return rate.Every(p.interval())
+}
+
+// interval is how long it takes to earn back one request, which is also when a denied caller may retry
+func (p RateLimitPolicy) interval() time.Duration {
+	return p.Per / time.Duration(p.Requests)
+}
+
+func (p RateLimitPolicy) burst() int {
+	if p.Burst > 0 {
+		return p.Burst
+	}
+	return max(p.Requests, 1)
+}
+
+func (p RateLimitPolicy) String() string {
+	s := fmt.Sprintf("%d/%s", p.Requests, p.Per)
+	if p.Burst > 0 {
+		s += fmt.Sprintf(" burst %d", p.Burst)
+	}
+	if p.Name != "" {
+		s = p.Name + " (" + s + ")"
+	}
+	return s
+}
 
 type RateLimitMiddleware struct {
 	server *server.Server
+
+	mu       sync.Mutex
+	policies map[string]RateLimitPolicy
+	stores   map[string]echoMiddleware.RateLimiterStore
 }
 
 func NewRateLimitMiddleware(s *server.Server) *RateLimitMiddleware{
 	return &RateLimitMiddleware{
-		server: s,
+		server:   s,
+		policies: map[string]RateLimitPolicy{},
+		stores:   map[string]echoMiddleware.RateLimiterStore{},
 	}
 }
 
@@ -18,4 +86,72 @@ func (r *RateLimitMiddleware) RecordRateLimitHit(endpoint string){
 			"endpoint": endpoint,
 		})
 	}
-}
\ No newline at end of file
+}
+
+// Limit enforces policy, authenticated callers are limited per user and everyone else per IP.
+// Every route mounting a policy with the same Name draws from the same store, unnamed policies get their own.
+// It panics on an invalid policy or a name reused with different settings so mistakes fail at startup.
+func (r *RateLimitMiddleware) Limit(policy RateLimitPolicy) echo.MiddlewareFunc {
+	store, err := r.store(policy)
+	if err != nil {
+		panic(err)
+	}
+
+	return echoMiddleware.RateLimiterWithConfig(echoMiddleware.RateLimiterConfig{
+		Store: store,
+		IdentifierExtractor: func(c echo.Context) (string, error) {
+			if userID := GetUserID(c); userID != "" {
+				return "user:" + userID, nil
+			}
+			return "ip:" + c.RealIP(), nil
+		},
+		DenyHandler: func(c echo.Context, identifier string, err error) error {
+			// Record rate limit hit metrics
+			r.RecordRateLimitHit(c.Path())
+
+			r.server.Logger.Warn().
+				Str("request_id", GetRequestID(c)).
+				Str("identifier", identifier).
+				Str("policy", policy.Name).
+				Str("path", c.Path()).
+				Str("method", c.Request().Method).
+				Str("ip", c.RealIP()).
+				Msg("rate limit exceeded")
+
+			return errs.NewTooManyRequestsError("Rate limit exceeded", false, policy.interval())
+		},
+	})
+}
+
+// store returns the shared store of a named policy, creating it on first use
+func (r *RateLimitMiddleware) store(policy RateLimitPolicy) (echoMiddleware.RateLimiterStore, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	newStore := func() echoMiddleware.RateLimiterStore { //should use redis currently using inmemory space
+		return echoMiddleware.NewRateLimiterMemoryStoreWithConfig(echoMiddleware.RateLimiterMemoryStoreConfig{
+			Rate:  policy.limit(),
+			Burst: policy.burst(),
+		})
+	}
+	if policy.Name == "" {
+		return newStore(), nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.policies[policy.Name]; ok {
+		if existing != policy {
+			return nil, fmt.Errorf("rate limit policy %s is mounted with different settings: %s and %s",
+				policy.Name, existing, policy)
+		}
+		return r.stores[policy.Name], nil
+	}
+
+	store := newStore()
+	r.policies[policy.Name] = policy
+	r.stores[policy.Name] = store
+	return store, nil
+}
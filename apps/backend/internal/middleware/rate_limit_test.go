@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Mayank85Y/boil/internal/config"
+	"github.com/Mayank85Y/boil/internal/server"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRateLimitMiddleware() *RateLimitMiddleware {
+	logger := zerolog.Nop()
+	return NewRateLimitMiddleware(&server.Server{Config: &config.Config{}, Logger: &logger})
+}
+
+func TestNamedRateLimitPolicyIsShared(t *testing.T) {
+	m := newTestRateLimitMiddleware()
+	policy := RateLimitPolicy{Name: "writes", Requests: 2, Per: time.Minute}
+
+	e := echo.New()
+	e.HTTPErrorHandler = NewGlobalMiddlewares(m.server).GlobalErrorHandler
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }
+	e.POST("/a", ok, m.Limit(policy))
+	e.POST("/b", ok, m.Limit(policy))
+	e.POST("/c", ok, m.Limit(RateLimitPolicy{Requests: 2, Per: time.Minute}))
+
+	status := func(path string) int {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, status("/a"))
+	assert.Equal(t, http.StatusNoContent, status("/b"))
+	assert.Equal(t, http.StatusTooManyRequests, status("/a"), "the budget is shared across routes")
+	assert.Equal(t, http.StatusTooManyRequests, status("/b"))
+	assert.Equal(t, http.StatusNoContent, status("/c"), "unnamed policies keep their own budget")
+}
+
+func TestRateLimitRetryAfterFollowsPolicy(t *testing.T) {
+	m := newTestRateLimitMiddleware()
+
+	e := echo.New()
+	e.HTTPErrorHandler = NewGlobalMiddlewares(m.server).GlobalErrorHandler
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }
+	e.POST("/slow", ok, m.Limit(RateLimitPolicy{Requests: 2, Per: time.Minute}))
+	e.POST("/fast", ok, m.Limit(RateLimitPolicy{Requests: 10, Per: time.Second, Burst: 1}))
+
+	for path, retryAfter := range map[string]string{"/slow": "30", "/fast": "1"} {
+		var rec *httptest.ResponseRecorder
+		for i := 0; i < 3; i++ {
+			rec = httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+		}
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code, path)
+		assert.Equal(t, retryAfter, rec.Header().Get("Retry-After"), path)
+	}
+}
+
+func TestInvalidRateLimitPolicyPanics(t *testing.T) {
+	m := newTestRateLimitMiddleware()
+
+	for name, policy := range map[string]RateLimitPolicy{
+		"no requests":    {Name: "a", Requests: 0, Per: time.Second},
+		"no period":      {Name: "b", Requests: 10},
+		"negative burst": {Name: "c", Requests: 10, Per: time.Second, Burst: -1},
+	} {
+		assert.Panics(t, func() { m.Limit(policy) }, name)
+	}
+
+	m.Limit(RateLimitPolicy{Name: "d", Requests: 10, Per: time.Second})
+	assert.Panics(t, func() { m.Limit(RateLimitPolicy{Name: "d", Requests: 20, Per: time.Second}) },
+		"same name with other settings")
+}
@@ -1,6 +1,9 @@
 package model
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -30,4 +33,26 @@ type PaginatedResponse[T interface{}] struct {
 	Limit      int `json:"limit"`
 	Total      int `json:"total"`
 	TotalPages int `json:"totalPages"`
-}
\ No newline at end of file
+}
+
+// Version is the weak ETag source of a row, it changes whenever updated_at does
+func (b BaseWithUpdatedAt) Version() string {
+	if b.UpdatedAt.IsZero() {
+		return ""
+	}
+	return strconv.FormatInt(b.UpdatedAt.UnixNano(), 36)
+}
+
+// Version combines the versions of the rows with the page position, it is empty when a row has none
+func (p PaginatedResponse[T]) Version() string {
+	parts := make([]string, 0, len(p.Data)+1)
+	parts = append(parts, fmt.Sprintf("%d.%d.%d", p.Page, p.Limit, p.Total))
+	for _, row := range p.Data {
+		versioned, ok := any(row).(interface{ Version() string })
+		if !ok || versioned.Version() == "" {
+			return ""
+		}
+		parts = append(parts, versioned.Version())
+	}
+	return strings.Join(parts, ",")
+}
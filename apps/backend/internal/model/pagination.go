@@ -0,0 +1,61 @@
+package model
+
+const (
+	// DefaultPageLimit is used when a request leaves limit out
+	DefaultPageLimit = 20
+
+	// MaxPageLimit is the largest limit any list endpoint accepts, routes can lower it
+	MaxPageLimit = 100
+)
+
+// PageQuery binds ?page=&limit= for list endpoints, embed it into the request type:
+//
+//	type ListTodosRequest struct {
+//		model.PageQuery
+//		Status string `query:"status"`
+//	}
+type PageQuery struct {
+	Page  int `query:"page" validate:"omitempty,min=1" doc:"Page number starting at 1, defaults to 1"`
+	Limit int `query:"limit" validate:"omitempty,min=1" doc:"Items per page, defaults to 20"`
+}
+
+// Pagination gives generic helpers access to an embedded PageQuery
+func (q PageQuery) Pagination() PageQuery {
+	return q
+}
+
+// WithDefaults fills in page 1 and defaultLimit for values the client left out
+func (q PageQuery) WithDefaults(defaultLimit int) PageQuery {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Limit < 1 {
+		q.Limit = defaultLimit
+	}
+	if q.Limit < 1 {
+		q.Limit = DefaultPageLimit
+	}
+	return q
+}
+
+// Offset is the number of rows to skip, use it with Limit in LIMIT/OFFSET queries
+func (q PageQuery) Offset() int {
+	q = q.WithDefaults(DefaultPageLimit)
+	return (q.Page - 1) * q.Limit
+}
+
+// NewPaginatedResponse wraps one page of rows, total is the row count without LIMIT/OFFSET
+func NewPaginatedResponse[T any](data []T, query PageQuery, total int) PaginatedResponse[T] {
+	query = query.WithDefaults(DefaultPageLimit)
+	if data == nil {
+		data = []T{}
+	}
+
+	return PaginatedResponse[T]{
+		Data:       data,
+		Page:       query.Page,
+		Limit:      query.Limit,
+		Total:      total,
+		TotalPages: (total + query.Limit - 1) / query.Limit,
+	}
+}
@@ -0,0 +1,156 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+)
+
+// SplitCatalog separates exact codes from {ENTITY} families (as regular expressions)
+func SplitCatalog(catalog []errs.CodeInfo) ([]string, []string) {
+	var exact, patterns []string
+	for _, info := range catalog {
+		if info.IsPattern() {
+			patterns = append(patterns, errs.EntityPattern(info.Code))
+			continue
+		}
+		exact = append(exact, info.Code)
+	}
+	return exact, patterns
+}
+
+// ErrorSchemas describes HTTPError and everything it references, shared by cmd/errcodes and the generated spec
+func ErrorSchemas(catalog []errs.CodeInfo) map[string]any {
+	exact, patterns := SplitCatalog(catalog)
+
+	codeOptions := []any{map[string]any{"type": "string", "enum": exact}}
+	for _, pattern := range patterns {
+		codeOptions = append(codeOptions, map[string]any{"type": "string", "pattern": pattern})
+	}
+
+	payloadRefs := []any{}
+	payloadByType := map[string]string{}
+	for _, ap := range actionPayloads {
+		ref := "#/components/schemas/" + ap.schemaName
+		payloadRefs = append(payloadRefs, map[string]any{"$ref": ref})
+		payloadByType[string(ap.actionType)] = ref
+	}
+
+	schemas := map[string]any{
+		"ErrorCode": map[string]any{
+			"description":     "Stable machine readable error code, see x-error-catalog for status and meaning",
+			"oneOf":           codeOptions,
+			"x-error-catalog": catalog,
+		},
+		"FieldError": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"field": map[string]any{"type": "string"},
+				"error": map[string]any{"type": "string"},
+				"location": map[string]any{
+					"type": "string",
+					"enum": []string{errs.FieldLocationBody, errs.FieldLocationQuery, errs.FieldLocationPath},
+				},
+			},
+			"required": []string{"field", "error"},
+		},
+		"ErrorAction": map[string]any{
+			"type":     "object",
+			"nullable": true,
+			"properties": map[string]any{
+				"type":    map[string]any{"type": "string", "enum": errs.ActionTypes()},
+				"message": map[string]any{"type": "string"},
+				"value":   map[string]any{"type": "string"},
+				"payload": map[string]any{
+					"description":       "Structured data for the action, the schema depends on type",
+					"oneOf":             payloadRefs,
+					"x-action-payloads": payloadByType,
+				},
+			},
+			"required": []string{"type", "message", "value"},
+		},
+		"ErrorDebug": map[string]any{
+			"description": "Wrapped error chain and stack, only returned to trusted debug callers",
+			"type":        "object",
+			"properties": map[string]any{
+				"chain": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				"stack": map[string]any{"type": "string"},
+			},
+			"required": []string{"chain"},
+		},
+		"HTTPError": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"code":     map[string]any{"$ref": "#/components/schemas/ErrorCode"},
+				"message":  map[string]any{"type": "string"},
+				"status":   map[string]any{"type": "integer"},
+				"override": map[string]any{"type": "boolean"},
+				"errors": map[string]any{
+					"type":     "array",
+					"nullable": true,
+					"items":    map[string]any{"$ref": "#/components/schemas/FieldError"},
+				},
+				"actions": map[string]any{"$ref": "#/components/schemas/ErrorAction"},
+				"details": map[string]any{"type": "object", "additionalProperties": true},
+				"debug":   map[string]any{"$ref": "#/components/schemas/ErrorDebug"},
+			},
+			"required": []string{"code", "message", "status", "override"},
+		},
+	}
+
+	for _, ap := range actionPayloads {
+		schemas[ap.schemaName] = flatStructSchema(reflect.TypeOf(ap.payload))
+	}
+	return schemas
+}
+
+// actionPayloads pairs every errs action type with its payload struct
+var actionPayloads = []struct {
+	actionType errs.ActionType
+	schemaName string
+	payload    any
+}{
+	{errs.ActionTypeRedirect, "RedirectActionPayload", errs.RedirectPayload{}},
+	{errs.ActionTypeReauthenticate, "ReauthenticateActionPayload", errs.ReauthenticatePayload{}},
+	{errs.ActionTypeRetry, "RetryActionPayload", errs.RetryPayload{}},
+	{errs.ActionTypeUpgradePlan, "UpgradePlanActionPayload", errs.UpgradePlanPayload{}},
+	{errs.ActionTypeRefresh, "RefreshActionPayload", errs.RefreshPayload{}},
+	{errs.ActionTypeContactSupport, "ContactSupportActionPayload", errs.ContactSupportPayload{}},
+}
+
+// flatStructSchema describes a flat struct of strings and integers from its json tags
+func flatStructSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		jsonType := "string"
+		switch field.Type.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			jsonType = "integer"
+		case reflect.Bool:
+			jsonType = "boolean"
+		}
+		properties[name] = map[string]any{"type": jsonType}
+
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
@@ -0,0 +1,313 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	nonIdentifier  = regexp.MustCompile(`[^A-Za-z0-9]+`)
+)
+
+// schemaBuilder reflects Go types into JSON schemas, named structs become components
+type schemaBuilder struct {
+	components map[string]any
+	names      map[reflect.Type]string
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{
+		components: map[string]any{},
+		names:      map[reflect.Type]string{},
+	}
+}
+
+// schemaFor returns the schema of t, a $ref for named structs
+func (b *schemaBuilder) schemaFor(t reflect.Type) map[string]any {
+	if t == nil {
+		return map[string]any{}
+	}
+
+	if t.Kind() == reflect.Pointer {
+		return nullable(b.schemaFor(t.Elem()))
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == rawMessageType:
+		return map[string]any{}
+	case t.PkgPath() == "github.com/google/uuid" && t.Name() == "UUID":
+		return map[string]any{"type": "string", "format": "uuid"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": b.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": b.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t, nil)
+		}
+		return b.ref(t)
+	default:
+		// interfaces and anything else accept any JSON value
+		return map[string]any{}
+	}
+}
+
+// ref registers a named struct as a component once and points at it
+func (b *schemaBuilder) ref(t reflect.Type) map[string]any {
+	name, ok := b.names[t]
+	if !ok {
+		name = b.componentName(t)
+		b.names[t] = name
+		// placeholder first so recursive types terminate
+		b.components[name] = map[string]any{}
+		b.components[name] = b.structSchema(t, nil)
+	}
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+// componentName turns Go type names into identifiers, generic instantiations included
+// (Page[github.com/x/model.User] -> PageUser), adding the package name on collisions
+func (b *schemaBuilder) componentName(t reflect.Type) string {
+	name := t.Name()
+	if base, args, ok := strings.Cut(name, "["); ok {
+		name = base
+		for _, arg := range strings.Split(strings.TrimSuffix(args, "]"), ",") {
+			parts := strings.Split(arg, ".")
+			name += parts[len(parts)-1]
+		}
+	}
+	name = nonIdentifier.ReplaceAllString(name, "")
+
+	if _, taken := b.components[name]; taken {
+		pkg := t.PkgPath()
+		name = nonIdentifier.ReplaceAllString(pkg[strings.LastIndex(pkg, "/")+1:], "") + name
+	}
+	return name
+}
+
+// structSchema describes the exported fields of t. include filters fields by their binding tag,
+// nil keeps everything sent as JSON.
+func (b *schemaBuilder) structSchema(t reflect.Type, include func(reflect.StructField) bool) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	b.addFields(t, include, properties, &required)
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (b *schemaBuilder) addFields(t reflect.Type, include func(reflect.StructField) bool,
+	properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, omitEmpty := jsonName(field)
+		if name == "-" {
+			continue
+		}
+
+		// embedded structs without a json name are flattened like encoding/json does
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			b.addFields(fieldType, include, properties, required)
+			continue
+		}
+
+		if include != nil && !include(field) {
+			continue
+		}
+		if include == nil && isURLBound(field) {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := b.fieldSchema(field)
+		properties[name] = schema
+
+		// required when validation demands it or encoding/json always writes it,
+		// optional request fields should be pointers or carry omitempty
+		alwaysWritten := !omitEmpty && field.Type.Kind() != reflect.Pointer && !hasRule(field, "omitempty")
+		if hasRule(field, "required") || alwaysWritten {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// fieldSchema is schemaFor plus what the validate and doc tags say about the field
+func (b *schemaBuilder) fieldSchema(field reflect.StructField) map[string]any {
+	base := b.schemaFor(field.Type)
+
+	schema := map[string]any{}
+	for k, v := range base {
+		schema[k] = v
+	}
+	if ref, ok := schema["$ref"]; ok {
+		// siblings of $ref are allowed in 3.1 but keep refs clean unless there is something to add
+		if field.Tag.Get("doc") == "" {
+			return schema
+		}
+		schema = map[string]any{"allOf": []any{map[string]any{"$ref": ref}}}
+	}
+
+	if doc := field.Tag.Get("doc"); doc != "" {
+		schema["description"] = doc
+	}
+	applyRules(schema, field)
+	return schema
+}
+
+// applyRules maps validate tags onto JSON schema keywords
+func applyRules(schema map[string]any, field reflect.StructField) {
+	kind := field.Type.Kind()
+	if kind == reflect.Pointer {
+		kind = field.Type.Elem().Kind()
+	}
+
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			// rules after dive apply to the items
+			return
+		case "min", "max", "len":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			keywords := map[string][2]string{
+				"min": {"minLength", "minItems"},
+				"max": {"maxLength", "maxItems"},
+				"len": {"minLength", "minItems"},
+			}[name]
+			switch kind {
+			case reflect.String:
+				schema[keywords[0]] = int(n)
+				if name == "len" {
+					schema["maxLength"] = int(n)
+				}
+			case reflect.Slice, reflect.Array, reflect.Map:
+				schema[keywords[1]] = int(n)
+				if name == "len" {
+					schema["maxItems"] = int(n)
+				}
+			default:
+				if name == "min" || name == "len" {
+					schema["minimum"] = n
+				}
+				if name == "max" || name == "len" {
+					schema["maximum"] = n
+				}
+			}
+		case "gte", "lte", "gt", "lt":
+			if n, err := strconv.ParseFloat(param, 64); err == nil {
+				schema[map[string]string{
+					"gte": "minimum", "lte": "maximum", "gt": "exclusiveMinimum", "lt": "exclusiveMaximum",
+				}[name]] = n
+			}
+		case "oneof":
+			var values []any
+			for _, value := range strings.Fields(param) {
+				if kind != reflect.String {
+					if n, err := strconv.ParseFloat(value, 64); err == nil {
+						values = append(values, n)
+						continue
+					}
+				}
+				values = append(values, value)
+			}
+			schema["enum"] = values
+		case "email":
+			schema["format"] = "email"
+		case "url", "uri":
+			schema["format"] = "uri"
+		case "uuid", "uuid4":
+			schema["format"] = "uuid"
+		case "e164":
+			schema["pattern"] = `^\+[1-9]\d{1,14}$`
+		case "slug":
+			schema["pattern"] = `^[a-z0-9]+(?:-[a-z0-9]+)*$`
+		case "country", "iso3166_1_alpha2":
+			schema["pattern"] = `^[A-Za-z]{2}$`
+		case "timezone":
+			schema["format"] = "timezone"
+		}
+	}
+}
+
+// nullable allows null next to schema, as a type list where possible
+func nullable(schema map[string]any) map[string]any {
+	if typ, ok := schema["type"].(string); ok {
+		cp := map[string]any{}
+		for k, v := range schema {
+			cp[k] = v
+		}
+		cp["type"] = []string{typ, "null"}
+		return cp
+	}
+	if len(schema) == 0 {
+		return schema
+	}
+	return map[string]any{"anyOf": []any{schema, map[string]any{"type": "null"}}}
+}
+
+// jsonName returns the json tag name ("" when unset) and whether omitempty is set
+func jsonName(field reflect.StructField) (string, bool) {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		return "", false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	return name, strings.Contains(opts, "omitempty") || strings.Contains(opts, "omitzero")
+}
+
+func isURLBound(field reflect.StructField) bool {
+	_, param := field.Tag.Lookup("param")
+	_, query := field.Tag.Lookup("query")
+	return param || query
+}
+
+func hasRule(field reflect.StructField, rule string) bool {
+	for _, r := range strings.Split(field.Tag.Get("validate"), ",") {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,441 @@
+// Package openapi builds the OpenAPI 3.1 document from the typed routes registered with handler.Routes,
+// so the spec always matches the Go request and response types.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+)
+
+// Version is the OpenAPI version of generated documents
+const Version = "3.1.0"
+
+const (
+	// SecuritySchemeBearer is required by operations registered with Auth
+	SecuritySchemeBearer = "bearerAuth"
+
+	errorSchemaRef = "#/components/schemas/HTTPError"
+)
+
+var echoParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// Operation is one registered route
+type Operation struct {
+	Method      string
+	Path        string // echo style, e.g. /api/v1/todos/:id
+	OperationID string
+	Summary     string
+	Description string
+	Tags        []string
+	Auth        bool
+	Permissions []string // listed as x-permissions, all are required
+	Status      int
+	Request     reflect.Type // nil when the route takes no input
+	Response    reflect.Type // nil for no content
+	ContentType string       // response media type, application/json when empty
+	// AltContentTypes are offered through content negotiation next to ContentType
+	AltContentTypes []string
+	// FileField documents a multipart/form-data body carrying up to MaxFiles binary files in that field,
+	// the body fields of Request are sent as form fields next to it
+	FileField string
+	MaxFiles  int
+	// Conditional documents the ETag header, If-None-Match and the 304 response
+	Conditional bool
+	// Paginated documents the Link and X-Total-Count headers of list responses
+	Paginated bool
+	// Idempotent documents the Idempotency-Key header and the 409 and 422 responses it can cause
+	Idempotent             bool
+	IdempotencyKeyRequired bool
+}
+
+// Registry collects operations, safe for concurrent use
+type Registry struct {
+	mu         sync.RWMutex
+	info       Info
+	servers    []Server
+	operations []Operation
+}
+
+func NewRegistry(info Info, servers ...Server) *Registry {
+	return &Registry{
+		info:    info,
+		servers: servers,
+	}
+}
+
+func (r *Registry) Add(op Operation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.operations = append(r.operations, op)
+}
+
+// Operations returns the registered operations sorted by path and method
+func (r *Registry) Operations() []Operation {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ops := append([]Operation(nil), r.operations...)
+	sort.SliceStable(ops, func(i, j int) bool {
+		if ops[i].Path != ops[j].Path {
+			return ops[i].Path < ops[j].Path
+		}
+		return ops[i].Method < ops[j].Method
+	})
+	return ops
+}
+
+// JSON renders the document, map keys are sorted by encoding/json so output is stable
+func (r *Registry) JSON() ([]byte, error) {
+	return json.MarshalIndent(r.Document(), "", "  ")
+}
+
+// Document builds the OpenAPI document
+func (r *Registry) Document() map[string]any {
+	builder := newSchemaBuilder()
+	paths := map[string]any{}
+
+	for _, op := range r.Operations() {
+		path := echoParam.ReplaceAllString(op.Path, "{$1}")
+		item, ok := paths[path].(map[string]any)
+		if !ok {
+			item = map[string]any{}
+			paths[path] = item
+		}
+		item[strings.ToLower(op.Method)] = operationObject(builder, op)
+	}
+
+	schemas := map[string]any{}
+	for name, schema := range ErrorSchemas(errs.Catalog()) {
+		schemas[name] = upgradeNullable(schema)
+	}
+	for name, schema := range builder.components {
+		schemas[name] = schema
+	}
+
+	doc := map[string]any{
+		"openapi": Version,
+		"info":    r.info,
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				SecuritySchemeBearer: map[string]any{
+					"type":         "http",
+					"scheme":       "bearer",
+					"bearerFormat": "JWT",
+				},
+			},
+		},
+	}
+	if len(r.servers) > 0 {
+		doc["servers"] = r.servers
+	}
+	return doc
+}
+
+func operationObject(builder *schemaBuilder, op Operation) map[string]any {
+	operation := map[string]any{
+		"operationId": op.OperationID,
+		"responses":   responses(builder, op),
+	}
+	if operation["operationId"] == "" {
+		operation["operationId"] = defaultOperationID(op.Method, op.Path)
+	}
+	if op.Summary != "" {
+		operation["summary"] = op.Summary
+	}
+	if op.Description != "" {
+		operation["description"] = op.Description
+	}
+	if len(op.Tags) > 0 {
+		operation["tags"] = op.Tags
+	}
+	if op.Auth {
+		operation["security"] = []any{map[string]any{SecuritySchemeBearer: []string{}}}
+	}
+	if len(op.Permissions) > 0 {
+		operation["x-permissions"] = op.Permissions
+	}
+
+	if op.FileField != "" {
+		operation["requestBody"] = uploadBody(builder, op)
+	}
+	if op.Request != nil {
+		request := op.Request
+		for request.Kind() == reflect.Pointer {
+			request = request.Elem()
+		}
+		if request.Kind() == reflect.Struct {
+			if params := parameters(builder, request); len(params) > 0 {
+				operation["parameters"] = params
+			}
+			if hasBody(op.Method) && hasBodyFields(request) && op.FileField == "" {
+				operation["requestBody"] = map[string]any{
+					"required": true,
+					"content": map[string]any{
+						"application/json": map[string]any{"schema": builder.schemaFor(request)},
+					},
+				}
+			}
+		}
+	}
+	if op.Conditional {
+		params, _ := operation["parameters"].([]any)
+		operation["parameters"] = append(params, map[string]any{
+			"name":        "If-None-Match",
+			"in":          "header",
+			"required":    false,
+			"description": "ETag of the cached response, answered with 304 while it is current",
+			"schema":      map[string]any{"type": "string"},
+		})
+	}
+	if op.Idempotent {
+		params, _ := operation["parameters"].([]any)
+		operation["parameters"] = append(params, map[string]any{
+			"name":        "Idempotency-Key",
+			"in":          "header",
+			"required":    op.IdempotencyKeyRequired,
+			"description": "Retries with the same key get the stored response instead of running the request again",
+			"schema":      map[string]any{"type": "string", "maxLength": 255},
+		})
+	}
+	return operation
+}
+
+// uploadBody is a multipart/form-data body with the file field next to the request fields
+func uploadBody(builder *schemaBuilder, op Operation) map[string]any {
+	file := map[string]any{"type": "string", "format": "binary"}
+	var field any = file
+	if op.MaxFiles > 1 {
+		field = map[string]any{"type": "array", "items": file, "maxItems": op.MaxFiles}
+	}
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{op.FileField: field},
+		"required":   []string{op.FileField},
+	}
+	if op.Request != nil {
+		request := op.Request
+		for request.Kind() == reflect.Pointer {
+			request = request.Elem()
+		}
+		if request.Kind() == reflect.Struct && hasBodyFields(request) {
+			schema = map[string]any{"allOf": []any{builder.schemaFor(request), schema}}
+		}
+	}
+
+	return map[string]any{
+		"required": true,
+		"content": map[string]any{
+			"multipart/form-data": map[string]any{"schema": schema},
+		},
+	}
+}
+
+func responses(builder *schemaBuilder, op Operation) map[string]any {
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	success := map[string]any{"description": http.StatusText(status)}
+	if op.Response != nil && status != http.StatusNoContent {
+		contentType := op.ContentType
+		schema := builder.schemaFor(op.Response)
+		if contentType == "" {
+			contentType = "application/json"
+		} else if contentType != "application/json" {
+			schema = map[string]any{"type": "string", "contentMediaType": contentType}
+		}
+		content := map[string]any{contentType: map[string]any{"schema": schema}}
+		for _, alt := range op.AltContentTypes {
+			altSchema := schema
+			if strings.HasPrefix(alt, "text/") {
+				altSchema = map[string]any{"type": "string", "contentMediaType": alt}
+			}
+			content[alt] = map[string]any{"schema": altSchema}
+		}
+		success["content"] = content
+	}
+	headers := map[string]any{}
+	if op.Conditional {
+		headers["ETag"] = map[string]any{"schema": map[string]any{"type": "string"}}
+	}
+	if op.Paginated {
+		headers["Link"] = map[string]any{
+			"description": "RFC 8288 links to the first, prev, next and last page",
+			"schema":      map[string]any{"type": "string"},
+		}
+		headers["X-Total-Count"] = map[string]any{
+			"description": "Number of items across all pages",
+			"schema":      map[string]any{"type": "integer"},
+		}
+	}
+	if len(headers) > 0 {
+		success["headers"] = headers
+	}
+
+	result := map[string]any{
+		strconv.Itoa(status): success,
+		"default":            errorResponse("Error"),
+	}
+	if op.Request != nil {
+		result[strconv.Itoa(http.StatusBadRequest)] = errorResponse("Invalid request")
+	}
+	if op.Auth {
+		result[strconv.Itoa(http.StatusUnauthorized)] = errorResponse("Not authenticated")
+	}
+	if len(op.Permissions) > 0 {
+		result[strconv.Itoa(http.StatusForbidden)] = errorResponse("Missing permissions")
+	}
+	if op.Conditional {
+		result[strconv.Itoa(http.StatusNotModified)] = map[string]any{"description": http.StatusText(http.StatusNotModified)}
+	}
+	if op.Idempotent {
+		result[strconv.Itoa(http.StatusBadRequest)] = errorResponse("Invalid request")
+		result[strconv.Itoa(http.StatusConflict)] = errorResponse("Idempotency-Key in use by a request in progress")
+		result[strconv.Itoa(http.StatusUnprocessableEntity)] = errorResponse("Idempotency-Key used for a different request")
+	}
+	if op.FileField != "" {
+		result[strconv.Itoa(http.StatusBadRequest)] = errorResponse("Invalid request")
+		result[strconv.Itoa(http.StatusRequestEntityTooLarge)] = errorResponse("File too large")
+		result[strconv.Itoa(http.StatusUnsupportedMediaType)] = errorResponse("File type not allowed")
+	}
+	return result
+}
+
+func errorResponse(description string) map[string]any {
+	schema := map[string]any{"$ref": errorSchemaRef}
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json":              map[string]any{"schema": schema},
+			errs.MIMEApplicationProblemJSON: map[string]any{"schema": map[string]any{"type": "object"}},
+		},
+	}
+}
+
+// parameters lists param (path) and query fields of the request type
+func parameters(builder *schemaBuilder, t reflect.Type) []any {
+	var params []any
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		in, name := "", ""
+		if value, ok := field.Tag.Lookup("param"); ok {
+			in, name = "path", value
+		} else if value, ok := field.Tag.Lookup("query"); ok {
+			in, name = "query", value
+		} else if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			params = append(params, parameters(builder, field.Type)...)
+			continue
+		} else {
+			continue
+		}
+
+		param := map[string]any{
+			"name":     strings.Split(name, ",")[0],
+			"in":       in,
+			"required": in == "path" || hasRule(field, "required"),
+			"schema":   builder.fieldSchema(field),
+		}
+		if doc := field.Tag.Get("doc"); doc != "" {
+			param["description"] = doc
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+func hasBody(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete:
+		return false
+	}
+	return true
+}
+
+// hasBodyFields reports whether any field is sent in the body rather than the URL
+func hasBodyFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if hasBodyFields(field.Type) {
+				return true
+			}
+			continue
+		}
+		if !isURLBound(field) {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultOperationID derives an id like getTodosById from the method and path
+func defaultOperationID(method string, path string) string {
+	id := strings.ToLower(method)
+	for _, segment := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '-' || r == '_' }) {
+		if strings.HasPrefix(segment, ":") {
+			id += "By"
+			segment = segment[1:]
+		}
+		if segment == "" {
+			continue
+		}
+		id += strings.ToUpper(segment[:1]) + segment[1:]
+	}
+	return id
+}
+
+// upgradeNullable rewrites OpenAPI 3.0 "nullable: true" into 3.1 type lists
+func upgradeNullable(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, child := range v {
+			out[k] = upgradeNullable(child)
+		}
+		if isNullable, _ := out["nullable"].(bool); isNullable {
+			delete(out, "nullable")
+			if typ, ok := out["type"].(string); ok {
+				out["type"] = []string{typ, "null"}
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, child := range v {
+			out[i] = upgradeNullable(child)
+		}
+		return out
+	default:
+		return v
+	}
+}
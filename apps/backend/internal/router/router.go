@@ -2,15 +2,12 @@ package router
 
 
 import (
-	"net/http"
-
 	"github.com/labstack/echo/v4"
-	echoMiddleware "github.com/labstack/echo/v4/middleware"
 	"github.com/Mayank85Y/boil/internal/handler"
 	"github.com/Mayank85Y/boil/internal/middleware"
+	"github.com/Mayank85Y/boil/internal/openapi"
 	"github.com/Mayank85Y/boil/internal/server"
 	"github.com/Mayank85Y/boil/internal/service"
-	"golang.org/x/time/rate"
 )
 
 func NewRouter(s *server.Server, h *handler.Handlers, services *service.Services) *echo.Echo {
@@ -22,25 +19,7 @@ func NewRouter(s *server.Server, h *handler.Handlers, services *service.Services
 
 	// global middlewares
 	router.Use(
-		echoMiddleware.RateLimiterWithConfig(echoMiddleware.RateLimiterConfig{  //should use redis currently using inmemory space
-			Store: echoMiddleware.NewRateLimiterMemoryStore(rate.Limit(20)),
-			DenyHandler: func(c echo.Context, identifier string, err error) error {
-				// Record rate limit hit metrics
-				if rateLimitMiddleware := middlewares.RateLimit; rateLimitMiddleware != nil {
-					rateLimitMiddleware.RecordRateLimitHit(c.Path())
-				}
-
-				s.Logger.Warn().
-					Str("request_id", middleware.GetRequestID(c)).
-					Str("identifier", identifier).
-					Str("path", c.Path()).
-					Str("method", c.Request().Method).
-					Str("ip", c.RealIP()).
-					Msg("rate limit exceeded")
-
-				return echo.NewHTTPError(http.StatusTooManyRequests, "Rate limit exceeded")
-			},
-		}),
+		middlewares.RateLimit.Limit(middleware.DefaultRateLimit),
 		middlewares.Global.CORS(),
 		middlewares.Global.Secure(),
 		middleware.RequestID(),
@@ -51,11 +30,31 @@ func NewRouter(s *server.Server, h *handler.Handlers, services *service.Services
 		middlewares.Global.Recover(),
 	)
 
-	// register system routes
-	registerSystemRoutes(router, h)
+	router.Static("/static", "static")
 
-	// register versioned routes
-	router.Group("/api/v1")
+	RegisterRoutes(handler.NewRoutes(router, NewSpec(), middlewares), h)
 
 	return router
+}
+
+// NewSpec returns the registry the documented routes are recorded in
+func NewSpec() *openapi.Registry {
+	return openapi.NewRegistry(
+		openapi.Info{
+			Title:       "Boilerplate REST API - Documentation",
+			Version:     "1.0.0",
+			Description: "Boilerplate REST API - Documentation",
+		},
+		openapi.Server{URL: "http://localhost:8080", Description: "Local Server"},
+	)
+}
+
+// RegisterRoutes mounts every route table, cmd/openapi and cmd/routes call it without a server
+func RegisterRoutes(routes *handler.Routes, h *handler.Handlers) {
+	// register system routes
+	registerSystemRoutes(routes, h)
+	registerDocsRoutes(routes, h)
+
+	// register versioned routes
+	registerV1Routes(routes.Group("/api/v1"), h)
 }
\ No newline at end of file
@@ -1,14 +1,33 @@
 package router
 
 import (
+	"net/http"
+
 	"github.com/Mayank85Y/boil/internal/handler"
-	"github.com/labstack/echo/v4"
 )
 
-func registerSystemRoutes(r *echo.Echo, h *handler.Handlers) {
-	r.GET("/status", h.Health.CheckHealth)
-
-	r.Static("/static", "static")
+func registerSystemRoutes(r *handler.Routes, h *handler.Handlers) {
+	r.Mount(
+		handler.Untyped(http.MethodGet, "/status", h.Health.CheckHealth, http.StatusOK, handler.RouteSpec{
+			OperationID: "getHealth",
+			Summary:     "Get health",
+			Description: "Get health status",
+			Tags:        []string{"Health"},
+			Response:    handler.HealthResponse{},
+		}),
+	)
+}
 
-	r.GET("/docs", h.OpenAPI.ServeOpenAPIUI)
-}
\ No newline at end of file
+// registerDocsRoutes serves the API docs, they are not part of the documented API themselves
+func registerDocsRoutes(r *handler.Routes, h *handler.Handlers) {
+	r.Mount(
+		handler.Untyped(http.MethodGet, "/docs", h.OpenAPI.ServeOpenAPIUI, http.StatusOK, handler.RouteSpec{
+			OperationID: "getDocs",
+			Hidden:      true,
+		}),
+		handler.Untyped(http.MethodGet, "/openapi.json", h.OpenAPI.ServeSpec(r.Spec()), http.StatusOK, handler.RouteSpec{
+			OperationID: "getOpenAPI",
+			Hidden:      true,
+		}),
+	)
+}
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"github.com/Mayank85Y/boil/internal/handler"
+)
+
+// registerV1Routes mounts the /api/v1 route table. Entries are built with handler.Typed and friends,
+// the RouteSpec of each entry declares auth, permissions, rate limit, timeout, body limit and idempotency, e.g.
+//
+//	handler.Typed(http.MethodPost, "/todos", h.Todo.Handler, h.Todo.CreateTodo, http.StatusCreated, handler.RouteSpec{
+//		OperationID: "createTodo",
+//		Tags:        []string{"Todos"},
+//		Permissions: []string{"org:todos:create"},
+//		RateLimit:   &middleware.RateLimitPolicy{Name: "writes", Requests: 30, Per: time.Minute},
+//		Timeout:     5 * time.Second,
+//		Idempotency: &middleware.IdempotencyPolicy{TTL: 24 * time.Hour},
+//	}),
+func registerV1Routes(r *handler.Routes, h *handler.Handlers) {
+	r.Mount()
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/Mayank85Y/boil/internal/config"
 	"github.com/Mayank85Y/boil/internal/database"
 	"github.com/Mayank85Y/boil/internal/lib/job"
+	"github.com/Mayank85Y/boil/internal/lib/storage"
 	loggerPkg "github.com/Mayank85Y/boil/internal/logger"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
@@ -24,6 +25,7 @@ type Server struct {
 	Redis 			*redis.Client
 	httpServer      *http.Server
 	Job 			*job.JobService
+	Storage			storage.Storage
 }
 
 func New(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerPkg.LoggerService) (*Server, error){
@@ -49,6 +51,12 @@ func New(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerPkg.Lo
 		logger.Error().Err(err).Msg("Failed to connect to redis, continuing without redis")
 	}
 
+	//uploads go to the local disk or an s3 compatible bucket, connected on the first upload
+	store, err := storage.NewLazy(cfg.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
 	//job service
 	jobService := job.NewJobService(logger, cfg)
 	jobService.InitHandlers(cfg, logger)
@@ -65,6 +73,7 @@ func New(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerPkg.Lo
 		DB:             db,
 		Redis: 			redisClient,	
 		Job: 			jobService,
+		Storage:		store,
 	}
 
 	//runtime metrics are auto collected by newrelic
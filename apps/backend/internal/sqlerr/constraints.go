@@ -0,0 +1,96 @@
+package sqlerr
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+)
+
+// Constraint describes how a violation of a named database constraint is reported to clients.
+// Modules register their constraints once (usually from the repository constructor) so
+// HandleError can produce precise codes and field errors instead of guessing from names.
+type Constraint struct {
+	// Code is the error code sent to the client, e.g. "USER_EMAIL_TAKEN"
+	Code string
+	// Field is the request field the constraint guards, e.g. "email" (optional)
+	Field string
+	// Message is the user facing message for the whole error
+	Message string
+	// FieldMessage is the field level error, defaults to a generic text for the violation type
+	FieldMessage string
+	// Status is the HTTP status, defaults to the status Code has in the errs catalog: 400 when Code is
+	// registered here, the {ENTITY} family status (e.g. 409 for exclusion constraints) when Code is empty
+	Status int
+}
+
+var (
+	constraintsMu sync.RWMutex
+	constraints   = map[string]Constraint{}
+)
+
+// RegisterConstraint adds a constraint to the registry.
+// It panics if the name is empty or already registered, like a duplicate route would.
+func RegisterConstraint(name string, c Constraint) {
+	constraintsMu.Lock()
+	defer constraintsMu.Unlock()
+
+	if name == "" {
+		panic("sqlerr: constraint name must not be empty")
+	}
+	if _, exists := constraints[name]; exists {
+		panic(fmt.Sprintf("sqlerr: constraint %q registered twice", name))
+	}
+	constraints[name] = c
+
+	// codes declared by modules belong in the central catalog too
+	if c.Code != "" && !errs.IsRegisteredCode(c.Code) {
+		status := c.Status
+		if status == 0 {
+			status = http.StatusBadRequest
+		}
+		errs.RegisterCode(errs.CodeInfo{
+			Code:        c.Code,
+			Status:      status,
+			Description: c.Message,
+			Overridable: true,
+		})
+	}
+}
+
+// RegisterConstraints registers every entry of the map, see RegisterConstraint
+func RegisterConstraints(cs map[string]Constraint) {
+	for name, c := range cs {
+		RegisterConstraint(name, c)
+	}
+}
+
+// LookupConstraint returns the registered constraint with the given name
+func LookupConstraint(name string) (Constraint, bool) {
+	constraintsMu.RLock()
+	defer constraintsMu.RUnlock()
+
+	c, ok := constraints[name]
+	return c, ok
+}
+
+// fieldMessages is the field level error per code, codes missing here get fieldInvalid
+var fieldMessages = map[Code]messageTemplate{
+	UniqueViolation:           {"field.already_exists", "already exists"},
+	ForeignKeyViolation:       {"field.not_exists", "does not exist"},
+	NotNullViolation:          {"field.required", "is required"},
+	ExcludeViolation:          {"field.conflict", "conflicts with an existing record"},
+	StringDataRightTruncation: {"field.too_long", "is too long"},
+	InvalidTextRepresentation: {"field.invalid_format", "has an invalid format"},
+}
+
+var fieldInvalid = messageTemplate{"field.invalid", "is invalid"}
+
+// fieldMessage is used when a registered constraint has no FieldMessage and for columns named by the database
+func fieldMessage(code Code) messageTemplate {
+	if template, ok := fieldMessages[code]; ok {
+		return template
+	}
+	return fieldInvalid
+}
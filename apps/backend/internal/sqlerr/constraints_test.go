@@ -0,0 +1,108 @@
+package sqlerr_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/Mayank85Y/boil/internal/sqlerr"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// whatever status a registered constraint answers with must be the one the catalog documents
+func TestRegisteredConstraintStatusMatchesCatalog(t *testing.T) {
+	sqlerr.RegisterConstraints(map[string]sqlerr.Constraint{
+		"bookings_room_during_excl": {Code: "BOOKING_ROOM_TAKEN", Field: "room"},
+		"bookings_desk_during_excl": {Code: "BOOKING_DESK_TAKEN", Status: http.StatusConflict},
+		"bookings_seat_during_excl": {Field: "seat"},
+		"users_email_key":           {Code: "USER_EMAIL_TAKEN", Field: "email", Message: "Email is taken"},
+	})
+
+	tests := []struct {
+		state      string
+		constraint string
+		code       string
+		status     int
+	}{
+		{"23P01", "bookings_room_during_excl", "BOOKING_ROOM_TAKEN", http.StatusBadRequest},
+		{"23P01", "bookings_desk_during_excl", "BOOKING_DESK_TAKEN", http.StatusConflict},
+		{"23P01", "bookings_seat_during_excl", "BOOKING_CONFLICT", http.StatusConflict},
+		{"23505", "users_email_key", "USER_EMAIL_TAKEN", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			httpErr := handle(t, &pgconn.PgError{Code: tt.state, TableName: "bookings", ConstraintName: tt.constraint})
+			assert.Equal(t, tt.code, httpErr.Code)
+			assert.Equal(t, tt.status, httpErr.Status)
+
+			info, ok := errs.LookupCode(httpErr.Code)
+			if assert.True(t, ok) {
+				assert.Equal(t, httpErr.Status, info.Status)
+			}
+		})
+	}
+}
+
+func TestRegisteredConstraintFieldErrors(t *testing.T) {
+	sqlerr.RegisterConstraints(map[string]sqlerr.Constraint{
+		"teams_slug_key":    {Code: "TEAM_SLUG_TAKEN", Field: "slug"},
+		"teams_owner_fkey":  {Code: "TEAM_OWNER_MISSING", Field: "ownerId", FieldMessage: "must name an existing user"},
+		"teams_name_check":  {Code: "TEAM_NAME_INVALID", Message: "Team names must not be empty"},
+		"teams_parent_fkey": {Field: "parentId"},
+	})
+
+	tests := []struct {
+		state      string
+		constraint string
+		code       string
+		message    string
+		key        string
+		field      *errs.FieldError
+	}{
+		{"23505", "teams_slug_key", "TEAM_SLUG_TAKEN", "A Team with this Slug already exists", "sqlerr.unique_field",
+			&errs.FieldError{Field: "slug", Error: "already exists", Key: "field.already_exists"}},
+		{"23503", "teams_owner_fkey", "TEAM_OWNER_MISSING", "The referenced Team does not exist", "sqlerr.foreign_key",
+			&errs.FieldError{Field: "ownerId", Error: "must name an existing user"}},
+		{"23514", "teams_name_check", "TEAM_NAME_INVALID", "Team names must not be empty", "", nil},
+		{"23503", "teams_parent_fkey", "TEAM_NOT_FOUND", "The referenced Team does not exist", "sqlerr.foreign_key",
+			&errs.FieldError{Field: "parentId", Error: "does not exist", Key: "field.not_exists"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			httpErr := handle(t, &pgconn.PgError{Code: tt.state, TableName: "teams", ConstraintName: tt.constraint})
+			assert.Equal(t, tt.code, httpErr.Code)
+			assert.Equal(t, tt.message, httpErr.Message)
+			assert.Equal(t, tt.key, httpErr.MessageKey)
+			if tt.field == nil {
+				assert.Empty(t, httpErr.Errors)
+				return
+			}
+			require.Len(t, httpErr.Errors, 1)
+			assert.Equal(t, *tt.field, httpErr.Errors[0])
+		})
+	}
+}
+
+func TestRegisterConstraint(t *testing.T) {
+	sqlerr.RegisterConstraint("projects_key_key", sqlerr.Constraint{Code: "PROJECT_KEY_TAKEN", Message: "Key is taken"})
+
+	c, ok := sqlerr.LookupConstraint("projects_key_key")
+	require.True(t, ok)
+	assert.Equal(t, "PROJECT_KEY_TAKEN", c.Code)
+
+	// module codes land in the catalog so the OpenAPI document lists them
+	info, ok := errs.LookupCode("PROJECT_KEY_TAKEN")
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, info.Status)
+	assert.Equal(t, "Key is taken", info.Description)
+
+	_, ok = sqlerr.LookupConstraint("projects_missing_key")
+	assert.False(t, ok)
+
+	assert.Panics(t, func() { sqlerr.RegisterConstraint("projects_key_key", sqlerr.Constraint{}) })
+	assert.Panics(t, func() { sqlerr.RegisterConstraint("", sqlerr.Constraint{}) })
+}
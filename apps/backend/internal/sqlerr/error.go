@@ -1,5 +1,12 @@
 package sqlerr
 
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
 // Code describes a specific type of database error.
 // The value Other is reported when an error does not map to any of the defined codes.
 type Code string
@@ -36,6 +43,44 @@ const (
 	// due to reaching the maximum number of connections.
 	// This is different from blocking waiting on a connection pool.
 	TooManyConnections Code = "too_many_connections"
+
+	// StringDataRightTruncation is reported when a value is too long for its column type.
+	StringDataRightTruncation Code = "string_data_right_truncation"
+
+	// InvalidTextRepresentation is reported when a value can't be parsed into the column type,
+	// e.g. a malformed UUID or integer.
+	InvalidTextRepresentation Code = "invalid_text_representation"
+
+	// DataException is reported for any other error in SQLSTATE class 22 (data exception).
+	DataException Code = "data_exception"
+
+	// IntegrityConstraintViolation is reported for any other error in SQLSTATE class 23.
+	IntegrityConstraintViolation Code = "integrity_constraint_violation"
+
+	// SerializationFailure is reported when a serializable or repeatable read transaction
+	// could not be committed because of concurrent updates. The transaction can be retried.
+	SerializationFailure Code = "serialization_failure"
+
+	// TransactionRollback is reported for any other error in SQLSTATE class 40.
+	TransactionRollback Code = "transaction_rollback"
+
+	// LockNotAvailable is reported when a lock could not be obtained immediately,
+	// e.g. SELECT ... FOR UPDATE NOWAIT or lock_timeout.
+	LockNotAvailable Code = "lock_not_available"
+
+	// QueryCanceled is reported when a statement was cancelled by the user or statement_timeout.
+	QueryCanceled Code = "query_canceled"
+
+	// InsufficientResources is reported for any other error in SQLSTATE class 53.
+	InsufficientResources Code = "insufficient_resources"
+
+	// ConnectionNotEstablished is reported when the client could not connect or the server
+	// rejected the connection (08001, 08004), so no statement ran.
+	ConnectionNotEstablished Code = "connection_not_established"
+
+	// ConnectionException is reported for any other error in SQLSTATE class 08. The connection
+	// broke mid-flight and it is unknown whether the transaction committed.
+	ConnectionException Code = "connection_exception"
 )
 
 // MapCode maps an underlying database error to a Code.
@@ -57,11 +102,59 @@ func MapCode(code string) Code {
 		return DeadlockDetected
 	case "53300":
 		return TooManyConnections
+	case "22001":
+		return StringDataRightTruncation
+	case "22P02":
+		return InvalidTextRepresentation
+	case "40001":
+		return SerializationFailure
+	case "55P03":
+		return LockNotAvailable
+	case "57014":
+		return QueryCanceled
+	case "08001", "08004":
+		return ConnectionNotEstablished
+	}
+
+	// fall back to the SQLSTATE class (first two characters)
+	if len(code) < 2 {
+		return Other
+	}
+	switch code[:2] {
+	case "08":
+		return ConnectionException
+	case "22":
+		return DataException
+	case "23":
+		return IntegrityConstraintViolation
+	case "40":
+		return TransactionRollback
+	case "53":
+		return InsufficientResources
 	default:
 		return Other
 	}
 }
 
+// IsRetryable reports whether an error is transient and the whole operation
+// (usually the transaction) can safely be run again. Besides the SQLSTATEs that ask for a retry this
+// covers failures before anything reached the server: dialing (*pgconn.ConnectError) and whatever pgconn
+// marks SafeToRetry. A context.DeadlineExceeded is retryable too, pgx cancels the statement and the
+// transaction rolls back; the retry needs a fresh context. Broken connections (ConnectionException)
+// are not retryable, the transaction may have committed and a retry would apply it twice.
+func IsRetryable(err error) bool {
+	switch ErrCode(err) {
+	case SerializationFailure, DeadlockDetected, LockNotAvailable, TooManyConnections, ConnectionNotEstablished:
+		return true
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) || pgconn.SafeToRetry(err) {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
 // Severity defines the severity of a database error.
 type Severity string
 
@@ -0,0 +1,90 @@
+package sqlerr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/Mayank85Y/boil/internal/sqlerr"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapCode(t *testing.T) {
+	tests := map[string]sqlerr.Code{
+		"23502": sqlerr.NotNullViolation,
+		"23503": sqlerr.ForeignKeyViolation,
+		"23505": sqlerr.UniqueViolation,
+		"23514": sqlerr.CheckViolation,
+		"23P01": sqlerr.ExcludeViolation,
+		"25P02": sqlerr.TransactionFailed,
+		"40P01": sqlerr.DeadlockDetected,
+		"53300": sqlerr.TooManyConnections,
+		"22001": sqlerr.StringDataRightTruncation,
+		"22P02": sqlerr.InvalidTextRepresentation,
+		"40001": sqlerr.SerializationFailure,
+		"55P03": sqlerr.LockNotAvailable,
+		"57014": sqlerr.QueryCanceled,
+		"08001": sqlerr.ConnectionNotEstablished,
+		"08004": sqlerr.ConnectionNotEstablished,
+		// anything else falls back to its class
+		"08006": sqlerr.ConnectionException,
+		"22012": sqlerr.DataException,
+		"23000": sqlerr.IntegrityConstraintViolation,
+		"40002": sqlerr.TransactionRollback,
+		"53200": sqlerr.InsufficientResources,
+		"42P01": sqlerr.Other,
+		"X":     sqlerr.Other,
+		"":      sqlerr.Other,
+	}
+
+	for state, code := range tests {
+		t.Run(state, func(t *testing.T) {
+			assert.Equal(t, code, sqlerr.MapCode(state))
+			assert.Equal(t, code, sqlerr.ErrCode(&pgconn.PgError{Code: state}))
+		})
+	}
+}
+
+// safeErr stands in for pgconn's unexported errors that implement SafeToRetry
+type safeErr struct{ safe bool }
+
+func (e safeErr) Error() string     { return "write failed" }
+func (e safeErr) SafeToRetry() bool { return e.safe }
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"serialization failure", &pgconn.PgError{Code: "40001"}, true},
+		{"deadlock", &pgconn.PgError{Code: "40P01"}, true},
+		{"lock not available", &pgconn.PgError{Code: "55P03"}, true},
+		{"too many connections", &pgconn.PgError{Code: "53300"}, true},
+		{"connection not established", &pgconn.PgError{Code: "08001"}, true},
+		{"dial failure", fmt.Errorf("ping: %w", &pgconn.ConnectError{Config: &pgconn.Config{}}), true},
+		{"nothing sent", fmt.Errorf("query: %w", safeErr{safe: true}), true},
+		{"deadline exceeded", fmt.Errorf("query: %w", context.DeadlineExceeded), true},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"connection lost", &pgconn.PgError{Code: "08006"}, false},
+		{"maybe sent", safeErr{safe: false}, false},
+		{"connection reset", io.ErrUnexpectedEOF, false},
+		{"canceled", context.Canceled, false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sqlerr.IsRetryable(tt.err))
+		})
+	}
+}
+
+func TestIsRetryableUnwrapsConvertedErrors(t *testing.T) {
+	err := fmt.Errorf("update: %w", sqlerr.ConvertPgError(&pgconn.PgError{Code: "40001"}))
+	assert.True(t, sqlerr.IsRetryable(err))
+	assert.False(t, sqlerr.IsRetryable(errors.New("40001")))
+}
@@ -4,8 +4,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"net/http"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/Mayank85Y/boil/internal/errs"
 	"github.com/jackc/pgx/v5"
@@ -14,12 +16,16 @@ import (
 	"golang.org/x/text/language"
 )
 
-//report err code for err if not *Error or nil then report sqlerr.Other
+//report err code for err (*Error or *pgconn.PgError), for anything else or nil report sqlerr.Other
 func ErrCode(err error) Code {
 	var pgerr *Error
 	if errors.As(err, &pgerr) {
 		return pgerr.Code
 	}
+	var pgconnErr *pgconn.PgError
+	if errors.As(err, &pgconnErr) {
+		return MapCode(pgconnErr.Code)
+	}
 	return Other
 }
 
@@ -43,55 +49,119 @@ func generateErrorCode(tableName string, errType Code) string {
 	if tableName == ""{
 		tableName = "Record"
 	}
-	domain := strings.ToUpper(tableName)
-	//singualrize table name
-	if strings.HasSuffix(domain, "S") && len(domain)>1{
-		domain = domain[:len(domain)-1]
-	}
-	
-	action := "ERROR"
+	domain := singularize(tableName)
+
+	pattern := errs.CodeInvalidData
 	switch errType {
 	case ForeignKeyViolation:
-		action = "NOT_FOUND"
+		pattern = errs.CodeEntityNotFound
 	case UniqueViolation:
-		action = "ALREADY_EXISTS"
+		pattern = errs.CodeEntityAlreadyExists
 	case NotNullViolation:
-		action = "REQUIRED"
+		pattern = errs.CodeEntityRequired
 	case CheckViolation:
-		action = "INVALID"
+		pattern = errs.CodeEntityInvalid
+	case ExcludeViolation:
+		pattern = errs.CodeEntityConflict
+	}
+
+	return entityCode(pattern, domain, errs.EntityRecord)
+}
+
+// entityCode is errs.EntityCode falling back to a generic entity when the name doesn't make a valid code
+func entityCode(pattern string, entity string, fallback string) string {
+	if code := errs.EntityCode(pattern, entity); errs.IsRegisteredCode(code) {
+		return code
 	}
+	return errs.EntityCode(pattern, fallback)
+}
+
+// messageTemplate is client facing text: key finds the translation in internal/i18n, text is the English
+// default. Both use the same {placeholders} so one set of params fills either.
+type messageTemplate struct {
+	key  string
+	text string
+}
 
-	return fmt.Sprintf("%s_%s", domain, action)
+func (m messageTemplate) format(params map[string]string) string {
+	text := m.text
+	for name, value := range params {
+		text = strings.ReplaceAll(text, "{"+name+"}", value)
+	}
+	return text
 }
 
-//generate user firendly mssg
-func formatUserFriendlyMessage(sqlErr *Error) string {
-	entityName := getEntityName(sqlErr.TableName, sqlErr.ColumnName)
-	
+// userMessage is the message of a whole database error, withField replaces generic when the error names a field
+type userMessage struct {
+	generic   messageTemplate
+	withField messageTemplate
+}
+
+var (
+	conflictMessage = userMessage{generic: messageTemplate{"sqlerr.conflict",
+		"The resource is being modified by another request, please try again"}}
+	unavailableMessage = userMessage{generic: messageTemplate{"sqlerr.unavailable",
+		"The service is temporarily unavailable, please try again later"}}
+
+	// unknownMessage has no key, it reads the same in every language as INTERNAL_SERVER_ERROR
+	unknownMessage = messageTemplate{text: "An error occurred while processing your request"}
+)
+
+var userMessages = map[Code]userMessage{
+	ForeignKeyViolation: {generic: messageTemplate{"sqlerr.foreign_key", "The referenced {entity} does not exist"}},
+	UniqueViolation: {
+		generic:   messageTemplate{"sqlerr.unique", "A {entity} with this identifier already exists"},
+		withField: messageTemplate{"sqlerr.unique_field", "A {entity} with this {field} already exists"},
+	},
+	NotNullViolation: {generic: messageTemplate{"sqlerr.not_null", "The {field} is required"}},
+	CheckViolation: {
+		generic:   messageTemplate{"sqlerr.check", "One or more values do not meet required conditions"},
+		withField: messageTemplate{"sqlerr.check_field", "The {field} value does not meet required conditions"},
+	},
+	ExcludeViolation: {generic: messageTemplate{"sqlerr.exclude", "The {entity} conflicts with an existing record"}},
+	StringDataRightTruncation: {
+		generic:   messageTemplate{"sqlerr.too_long", "One or more values are too long"},
+		withField: messageTemplate{"sqlerr.too_long_field", "The {field} value is too long"},
+	},
+	InvalidTextRepresentation: {generic: messageTemplate{"sqlerr.invalid_format", "One or more values have an invalid format"}},
+	DataException:             {generic: messageTemplate{"sqlerr.invalid_data", "One or more values are invalid"}},
+	SerializationFailure:      conflictMessage,
+	DeadlockDetected:          conflictMessage,
+	TransactionRollback:       conflictMessage,
+	LockNotAvailable:          conflictMessage,
+	QueryCanceled:             {generic: messageTemplate{"sqlerr.timeout", "The request took too long to process, please try again"}},
+	TooManyConnections:        unavailableMessage,
+	InsufficientResources:     unavailableMessage,
+	ConnectionNotEstablished:  unavailableMessage,
+	ConnectionException:       unavailableMessage,
+}
+
+//pick the user friendly message of a database error and the params filling it
+func userMessageFor(sqlErr *Error) (messageTemplate, map[string]string) {
+	params := map[string]string{
+		"entity": getEntityName(sqlErr.TableName, sqlErr.ColumnName),
+		"field":  humanizeText(sqlErr.ColumnName),
+	}
 	switch sqlErr.Code {
-	case ForeignKeyViolation:
-		return fmt.Sprintf("The referenced %s does not exist", entityName)
 	case UniqueViolation:
-		return fmt.Sprintf("A %s with this identifier already exists", entityName)
+		// postgres doesn't report the column, only constraints following the naming convention tell
+		params["field"] = humanizeText(extractColumnForUniqueViolation(sqlErr.ConstraintName))
 	case NotNullViolation:
-		fieldName := humanizeText(sqlErr.ColumnName)
-		if fieldName == "" {
-			fieldName = "field"
+		if params["field"] == "" {
+			params["field"] = "field"
 		}
-		return fmt.Sprintf("The %s is required", fieldName)
-	case CheckViolation:
-		fieldName := humanizeText(sqlErr.ColumnName)
-		if fieldName != "" {
-			return fmt.Sprintf("The %s value does not meet required conditions", fieldName)
-		}
-		return "One or more values do not meet required conditions"
-	default:
-		return "An error occurred while processing your request"
 	}
 
+	message, ok := userMessages[sqlErr.Code]
+	if !ok {
+		return unknownMessage, nil
+	}
+	if message.withField.key != "" && params["field"] != "" {
+		return message.withField, params
+	}
+	return message.generic, params
 }
 
-
 func getEntityName(tableName string, columnName string) string{
 	//1st priority: col name logic (Most reliable for fk.relationship)
 	if columnName != "" && strings.HasSuffix(strings.ToLower(columnName),"_id"){
@@ -102,16 +172,32 @@ func getEntityName(tableName string, columnName string) string{
 	//2nd priority: table name(fallback options)
 	if tableName != ""{
 		//use singualr form
-		entity := tableName
-		if strings.HasSuffix(entity,"s") && len(entity)>1{
-			entity = entity[:len(entity)-1]
-		}
-		return humanizeText(entity)
+		return humanizeText(singularize(tableName))
 	}
 
 	return "record" //default fallback
 }
 
+//best effort english singular for table names (addresses -> address, categories -> category)
+func singularize(word string) string {
+	lower := strings.ToLower(word)
+	switch {
+	case len(word) <= 1:
+		return word
+	case strings.HasSuffix(lower, "ies") && len(word) > 3:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "zes"),
+		strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(lower, "ss"), strings.HasSuffix(lower, "us"):
+		return word
+	case strings.HasSuffix(lower, "s"):
+		return word[:len(word)-1]
+	default:
+		return word
+	}
+}
+
 //snake_case to human-readable txt
 func humanizeText(text string) string {
 	if text == "" {
@@ -142,64 +228,198 @@ func extractColumnForUniqueViolation(constraintName string) string{
 	return ""
 }
 
-//process db err into app err
-func HandleError(err error) error {
-	// If it's already a custom HTTP error, just return it
-	var httpErr *errs.HTTPError
-	if errors.As(err, &httpErr) {
-		return err
+var dataExceptionCodes = map[Code]string{
+	StringDataRightTruncation: errs.CodeValueTooLong,
+	InvalidTextRepresentation: errs.CodeInvalidFormat,
+	DataException:             errs.CodeInvalidData,
+}
+
+// how long clients should wait before retrying transient database errors
+const (
+	conflictRetryAfter    = time.Second
+	unavailableRetryAfter = 5 * time.Second
+)
+
+//build app err from a constraint declared through RegisterConstraint
+func handleRegisteredConstraint(sqlErr *Error, constraint Constraint) *errs.HTTPError {
+	code := constraint.Code
+	if code == "" {
+		code = generateErrorCode(sqlErr.TableName, sqlErr.Code)
 	}
 
-	// Handle pgx specific errors
-	var pgerr *pgconn.PgError
-	if errors.As(err, &pgerr) {
-		sqlErr := ConvertPgError(pgerr)
+	message := constraint.Message
+	messageKey, messageParams := "", map[string]string(nil)
+	if message == "" {
+		var template messageTemplate
+		template, messageParams = userMessageFor(sqlErr)
+		message, messageKey = template.format(messageParams), template.key
+	}
 
-		// Generate an appropriate error code and message
-		errorCode := generateErrorCode(sqlErr.TableName, sqlErr.Code)
-		userMessage := formatUserFriendlyMessage(sqlErr)
+	var fieldErrors []errs.FieldError
+	if constraint.Field != "" {
+		fieldError := errs.FieldError{
+			Field: constraint.Field,
+			Error: constraint.FieldMessage,
+		}
+		if fieldError.Error == "" {
+			template := fieldMessage(sqlErr.Code)
+			fieldError.Error, fieldError.Key = template.text, template.key
+		}
+		fieldErrors = []errs.FieldError{fieldError}
+	}
 
-		switch sqlErr.Code {
-		case ForeignKeyViolation:
-			return errs.NewBadRequestError(userMessage, false, &errorCode, nil, nil)
+	// the catalog documents the status, answer with the same one
+	status := constraint.Status
+	if status == 0 {
+		status = http.StatusBadRequest
+		if info, ok := errs.LookupCode(code); ok {
+			status = info.Status
+		}
+	}
 
-		case UniqueViolation:
-			columnName := extractColumnForUniqueViolation(sqlErr.ConstraintName)
-			if columnName != "" {
-				userMessage = strings.ReplaceAll(userMessage, "identifier", humanizeText(columnName))
-			}
-			return errs.NewBadRequestError(userMessage, true, &errorCode, nil, nil)
+	if status != http.StatusBadRequest {
+		return &errs.HTTPError{
+			Code:          code,
+			Message:       message,
+			Status:        status,
+			Override:      true,
+			Errors:        fieldErrors,
+			MessageKey:    messageKey,
+			MessageParams: messageParams,
+		}
+	}
+	return errs.NewBadRequestError(message, true, &code, fieldErrors, nil).WithMessageKey(messageKey, messageParams)
+}
+
+//build 404 with <ENTITY>_NOT_FOUND code and the lookup as details
+func handleNotFound(nf *NotFoundError) *errs.HTTPError {
+	entity := nf.Entity
+	if entity == "" {
+		entity = "resource"
+	}
+
+	code := entityCode(errs.CodeEntityNotFound, entity, errs.EntityResource)
+	httpErr := errs.NewNotFoundError(fmt.Sprintf("%s not found", humanizeText(entity)), true, &code).
+		WithMessageKey("sqlerr.not_found", map[string]string{"entity": humanizeText(entity)})
+
+	httpErr.Details = map[string]any{
+		"entity": entity,
+	}
+	if nf.Key != "" {
+		httpErr.Details["lookup"] = map[string]any{
+			nf.Key: nf.Value,
+		}
+	}
+	return httpErr
+}
+
+//map a database error onto the matching app err
+func handlePgError(sqlErr *Error) *errs.HTTPError {
+	// registered constraints know exactly which field and code to report
+	if sqlErr.ConstraintName != "" {
+		if constraint, ok := LookupConstraint(sqlErr.ConstraintName); ok {
+			return handleRegisteredConstraint(sqlErr, constraint)
+		}
+	}
+
+	template, params := userMessageFor(sqlErr)
+	httpErr := mapPgError(sqlErr, template.format(params))
+	if template.key != "" {
+		return httpErr.WithMessageKey(template.key, params)
+	}
+	return httpErr
+}
+
+func mapPgError(sqlErr *Error, userMessage string) *errs.HTTPError {
+	// Generate an appropriate error code
+	errorCode := generateErrorCode(sqlErr.TableName, sqlErr.Code)
+
+	switch sqlErr.Code {
+	case ForeignKeyViolation:
+		return errs.NewBadRequestError(userMessage, false, &errorCode, nil, nil)
+
+	case UniqueViolation:
+		return errs.NewBadRequestError(userMessage, true, &errorCode, nil, nil)
+
+	case NotNullViolation:
+		required := fieldMessage(NotNullViolation)
+		fieldErrors := []errs.FieldError{
+			{
+				Field: strings.ToLower(sqlErr.ColumnName),
+				Error: required.text,
+				Key:   required.key,
+			},
+		}
+		return errs.NewBadRequestError(userMessage, true, &errorCode, fieldErrors, nil)
 
-		case NotNullViolation:
-			fieldErrors := []errs.FieldError{
+	case CheckViolation:
+		return errs.NewBadRequestError(userMessage, true, &errorCode, nil, nil)
+
+	case ExcludeViolation:
+		return errs.NewConflictError(userMessage, true, &errorCode)
+
+	case StringDataRightTruncation, InvalidTextRepresentation, DataException:
+		code := dataExceptionCodes[sqlErr.Code]
+		var fieldErrors []errs.FieldError
+		if sqlErr.ColumnName != "" {
+			template := fieldMessage(sqlErr.Code)
+			fieldErrors = []errs.FieldError{
 				{
 					Field: strings.ToLower(sqlErr.ColumnName),
-					Error: "is required",
+					Error: template.text,
+					Key:   template.key,
 				},
 			}
-			return errs.NewBadRequestError(userMessage, true, &errorCode, fieldErrors, nil)
+		}
+		return errs.NewBadRequestError(userMessage, true, &code, fieldErrors, nil)
 
-		case CheckViolation:
-			return errs.NewBadRequestError(userMessage, true, &errorCode, nil, nil)
+	case SerializationFailure, DeadlockDetected, TransactionRollback:
+		code := errs.CodeTransactionConflict
+		return errs.NewConflictError(userMessage, true, &code).
+			WithAction(errs.NewRetryAction("Please try again", conflictRetryAfter))
 
-		default:
-			return errs.NewInternalServerError()
-		}
+	case LockNotAvailable:
+		code := errs.CodeResourceLocked
+		return errs.NewConflictError(userMessage, true, &code).
+			WithAction(errs.NewRetryAction("Please try again", conflictRetryAfter))
+
+	case QueryCanceled:
+		code := errs.CodeQueryTimeout
+		return errs.NewServiceUnavailableError(userMessage, true, &code, 0)
+
+	case TooManyConnections, InsufficientResources, ConnectionNotEstablished, ConnectionException:
+		return errs.NewServiceUnavailableError(userMessage, true, nil, unavailableRetryAfter)
+
+	default:
+		return errs.NewInternalServerError()
+	}
+}
+
+//process db err into app err
+func HandleError(err error) error {
+	// If it's already a custom HTTP error, just return it
+	var httpErr *errs.HTTPError
+	if errors.As(err, &httpErr) {
+		return err
+	}
+
+	// Typed not found errors returned by repositories
+	var notFoundErr *NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return handleNotFound(notFoundErr).WithCause(err)
 	}
 
-	// Handle common pgx errors
+	// Handle pgx specific errors
+	var pgerr *pgconn.PgError
+	if errors.As(err, &pgerr) {
+		return handlePgError(ConvertPgError(pgerr)).WithCause(err)
+	}
+
+	// Handle common pgx errors, repositories should wrap these in NotFoundError (see WrapNoRows)
 	switch {
 	case errors.Is(err, pgx.ErrNoRows), errors.Is(err, sql.ErrNoRows):
-		errMsg := err.Error()
-		tablePrefix := "table:"
-		if strings.Contains(errMsg, tablePrefix) {
-			table := strings.Split(strings.Split(errMsg, tablePrefix)[1], ":")[0]
-			entityName := getEntityName(table, "")
-			return errs.NewNotFoundError(fmt.Sprintf("%s not found",
-				entityName), true, nil)
-		}
-		return errs.NewNotFoundError("Resource not found", false, nil)
+		return errs.NewNotFoundError("Resource not found", false, nil).WithCause(err)
 	}
 
-	return errs.NewInternalServerError()
+	return errs.NewInternalServerError().WithCause(err)
 }
\ No newline at end of file
@@ -0,0 +1,152 @@
+package sqlerr_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/Mayank85Y/boil/internal/sqlerr"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func handle(t *testing.T, err error) *errs.HTTPError {
+	t.Helper()
+	var httpErr *errs.HTTPError
+	require.True(t, errors.As(sqlerr.HandleError(err), &httpErr))
+	return httpErr
+}
+
+func TestHandleErrorMapsSQLState(t *testing.T) {
+	tests := []struct {
+		state  string
+		code   string
+		status int
+		key    string
+		retry  bool // carries a retry action
+	}{
+		{"22001", errs.CodeValueTooLong, http.StatusBadRequest, "sqlerr.too_long_field", false},
+		{"22P02", errs.CodeInvalidFormat, http.StatusBadRequest, "sqlerr.invalid_format", false},
+		{"22012", errs.CodeInvalidData, http.StatusBadRequest, "sqlerr.invalid_data", false},
+		{"40001", errs.CodeTransactionConflict, http.StatusConflict, "sqlerr.conflict", true},
+		{"40P01", errs.CodeTransactionConflict, http.StatusConflict, "sqlerr.conflict", true},
+		{"40002", errs.CodeTransactionConflict, http.StatusConflict, "sqlerr.conflict", true},
+		{"55P03", errs.CodeResourceLocked, http.StatusConflict, "sqlerr.conflict", true},
+		{"57014", errs.CodeQueryTimeout, http.StatusServiceUnavailable, "sqlerr.timeout", false},
+		{"53300", errs.CodeServiceUnavailable, http.StatusServiceUnavailable, "sqlerr.unavailable", true},
+		{"53200", errs.CodeServiceUnavailable, http.StatusServiceUnavailable, "sqlerr.unavailable", true},
+		{"08001", errs.CodeServiceUnavailable, http.StatusServiceUnavailable, "sqlerr.unavailable", true},
+		{"08006", errs.CodeServiceUnavailable, http.StatusServiceUnavailable, "sqlerr.unavailable", true},
+		{"42P01", errs.CodeInternalServerError, http.StatusInternalServerError, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			pgErr := &pgconn.PgError{Code: tt.state, TableName: "users", ColumnName: "name"}
+			httpErr := handle(t, pgErr)
+			assert.Equal(t, tt.code, httpErr.Code)
+			assert.Equal(t, tt.status, httpErr.Status)
+			assert.Equal(t, tt.key, httpErr.MessageKey)
+			assert.Equal(t, tt.retry, httpErr.Action != nil)
+			assert.ErrorIs(t, httpErr, pgErr)
+		})
+	}
+}
+
+// columns postgres names in the error become field errors
+func TestHandleErrorFieldErrors(t *testing.T) {
+	tests := []struct {
+		state string
+		error string
+		key   string
+	}{
+		{"23502", "is required", "field.required"},
+		{"22001", "is too long", "field.too_long"},
+		{"22P02", "has an invalid format", "field.invalid_format"},
+		{"22012", "is invalid", "field.invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			httpErr := handle(t, &pgconn.PgError{Code: tt.state, TableName: "users", ColumnName: "Email"})
+			require.Len(t, httpErr.Errors, 1)
+			assert.Equal(t, errs.FieldError{Field: "email", Error: tt.error, Key: tt.key}, httpErr.Errors[0])
+		})
+	}
+
+	httpErr := handle(t, &pgconn.PgError{Code: "22001", TableName: "users"})
+	assert.Empty(t, httpErr.Errors)
+	assert.Equal(t, "sqlerr.too_long", httpErr.MessageKey)
+}
+
+func TestHandleErrorDerivesEntityCodes(t *testing.T) {
+	tests := []struct {
+		state  string
+		table  string
+		code   string
+		status int
+	}{
+		{"23505", "users", "USER_ALREADY_EXISTS", http.StatusBadRequest},
+		{"23503", "order_items", "ORDER_ITEM_NOT_FOUND", http.StatusBadRequest},
+		{"23502", "addresses", "ADDRESS_REQUIRED", http.StatusBadRequest},
+		{"23514", "categories", "CATEGORY_INVALID", http.StatusBadRequest},
+		{"23P01", "bookings", "BOOKING_CONFLICT", http.StatusConflict},
+		{"23505", "", "RECORD_ALREADY_EXISTS", http.StatusBadRequest},
+		{"23505", "2fa_codes", "RECORD_ALREADY_EXISTS", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.state+" "+tt.table, func(t *testing.T) {
+			httpErr := handle(t, &pgconn.PgError{Code: tt.state, TableName: tt.table, ColumnName: "name"})
+			assert.Equal(t, tt.code, httpErr.Code)
+			assert.Equal(t, tt.status, httpErr.Status)
+			assert.True(t, errs.IsRegisteredCode(httpErr.Code))
+		})
+	}
+}
+
+func TestHandleErrorUniqueMessageNamesColumn(t *testing.T) {
+	httpErr := handle(t, &pgconn.PgError{Code: "23505", TableName: "accounts", ConstraintName: "accounts_email_key"})
+	assert.Equal(t, "A Account with this Email already exists", httpErr.Message)
+	assert.Equal(t, "sqlerr.unique_field", httpErr.MessageKey)
+	assert.Equal(t, "Email", httpErr.MessageParams["field"])
+}
+
+func TestHandleErrorNotFoundCodes(t *testing.T) {
+	tests := map[string]string{
+		"user":       "USER_NOT_FOUND",
+		"order_item": "ORDER_ITEM_NOT_FOUND",
+		"order item": "ORDER_ITEM_NOT_FOUND",
+		"":           "RESOURCE_NOT_FOUND",
+		"2fa code":   "RESOURCE_NOT_FOUND",
+	}
+
+	for entity, code := range tests {
+		t.Run(entity, func(t *testing.T) {
+			httpErr := handle(t, sqlerr.NewNotFoundError(entity, "id", 7, nil))
+			assert.Equal(t, code, httpErr.Code)
+			assert.Equal(t, http.StatusNotFound, httpErr.Status)
+			assert.Equal(t, map[string]any{"id": 7}, httpErr.Details["lookup"])
+		})
+	}
+}
+
+func TestHandleErrorNoRows(t *testing.T) {
+	for _, err := range []error{pgx.ErrNoRows, sql.ErrNoRows} {
+		httpErr := handle(t, fmt.Errorf("find user: %w", err))
+		assert.Equal(t, errs.CodeNotFound, httpErr.Code)
+		assert.Equal(t, http.StatusNotFound, httpErr.Status)
+	}
+
+	httpErr := handle(t, errors.New("boom"))
+	assert.Equal(t, http.StatusInternalServerError, httpErr.Status)
+
+	// errors that already are HTTP errors pass through untouched
+	forbidden := errs.NewForbiddenError("nope", true)
+	assert.Same(t, forbidden, handle(t, forbidden))
+}
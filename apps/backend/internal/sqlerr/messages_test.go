@@ -0,0 +1,47 @@
+package sqlerr
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/Mayank85Y/boil/internal/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+var placeholder = regexp.MustCompile(`\{[a-z]+\}`)
+
+// every key in the message tables needs a translation filled from the same params as the English text
+func TestMessageTemplatesAreTranslated(t *testing.T) {
+	templates := []messageTemplate{fieldInvalid}
+	for _, message := range userMessages {
+		templates = append(templates, message.generic)
+		if message.withField.key != "" {
+			templates = append(templates, message.withField)
+		}
+	}
+	for _, template := range fieldMessages {
+		templates = append(templates, template)
+	}
+
+	for _, template := range templates {
+		english := placeholder.FindAllString(template.text, -1)
+		for _, tag := range i18n.Supported()[1:] {
+			translated, ok := i18n.Lookup(tag, template.key, nil)
+			if assert.True(t, ok, "%s has no %s translation", template.key, tag) {
+				for _, name := range placeholder.FindAllString(translated, -1) {
+					assert.Contains(t, english, name, "%s in %s", template.key, tag)
+				}
+			}
+		}
+	}
+}
+
+func TestUserMessageNamesUniqueColumn(t *testing.T) {
+	template, params := userMessageFor(&Error{Code: UniqueViolation, TableName: "users", ConstraintName: "users_email_key"})
+	assert.Equal(t, "sqlerr.unique_field", template.key)
+	assert.Equal(t, "A User with this Email already exists", template.format(params))
+
+	template, params = userMessageFor(&Error{Code: UniqueViolation, TableName: "users", ConstraintName: "users_pkey"})
+	assert.Equal(t, "sqlerr.unique", template.key)
+	assert.Equal(t, "A User with this identifier already exists", template.format(params))
+}
@@ -0,0 +1,65 @@
+package sqlerr
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// NotFoundError is returned by repositories when a lookup matched no rows.
+// HandleError turns it into a 404 with the code <ENTITY>_NOT_FOUND and the
+// lookup key as error details.
+type NotFoundError struct {
+	// Entity is the singular name of what was looked up, e.g. "user" or "order_item"
+	Entity string
+	// Key is the attribute used for the lookup, e.g. "id" or "email"
+	Key string
+	// Value is the requested identifier
+	Value any
+
+	err error
+}
+
+// NewNotFoundError creates a NotFoundError. cause is usually pgx.ErrNoRows and may be nil.
+func NewNotFoundError(entity string, key string, value any, cause error) *NotFoundError {
+	if cause == nil {
+		cause = pgx.ErrNoRows
+	}
+	return &NotFoundError{
+		Entity: entity,
+		Key:    key,
+		Value:  value,
+		err:    cause,
+	}
+}
+
+// WrapNoRows converts "no rows" errors into a NotFoundError and returns any other error unchanged
+//
+//	if err := row.Scan(&user.ID, &user.Email); err != nil {
+//		return nil, sqlerr.WrapNoRows(err, "user", "id", id)
+//	}
+func WrapNoRows(err error, entity string, key string, value any) error {
+	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+		return NewNotFoundError(entity, key, value, err)
+	}
+	return err
+}
+
+func (e *NotFoundError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("%s not found", e.Entity)
+	}
+	return fmt.Sprintf("%s not found (%s: %v)", e.Entity, e.Key, e.Value)
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return e.err
+}
+
+// IsNotFound reports whether err is a NotFoundError or a bare "no rows" error
+func IsNotFound(err error) bool {
+	var nf *NotFoundError
+	return errors.As(err, &nf) || errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows)
+}
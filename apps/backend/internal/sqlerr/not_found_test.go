@@ -0,0 +1,55 @@
+package sqlerr_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Mayank85Y/boil/internal/sqlerr"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapNoRows(t *testing.T) {
+	for _, noRows := range []error{pgx.ErrNoRows, sql.ErrNoRows, fmt.Errorf("scan: %w", pgx.ErrNoRows)} {
+		err := sqlerr.WrapNoRows(noRows, "user", "email", "ada@example.com")
+
+		var nf *sqlerr.NotFoundError
+		require.True(t, errors.As(err, &nf), noRows)
+		assert.Equal(t, "user", nf.Entity)
+		assert.Equal(t, "user not found (email: ada@example.com)", nf.Error())
+		assert.ErrorIs(t, err, noRows)
+		assert.True(t, sqlerr.IsNotFound(err))
+	}
+
+	other := errors.New("connection refused")
+	assert.Same(t, other, sqlerr.WrapNoRows(other, "user", "id", 1))
+	assert.Nil(t, sqlerr.WrapNoRows(nil, "user", "id", 1))
+}
+
+func TestIsNotFound(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"typed", sqlerr.NewNotFoundError("user", "", nil, nil), true},
+		{"wrapped typed", fmt.Errorf("load: %w", sqlerr.NewNotFoundError("user", "id", 1, nil)), true},
+		{"pgx", pgx.ErrNoRows, true},
+		{"database/sql", sql.ErrNoRows, true},
+		{"other", errors.New("boom"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sqlerr.IsNotFound(tt.err))
+		})
+	}
+
+	nf := sqlerr.NewNotFoundError("user", "", nil, nil)
+	assert.Equal(t, "user not found", nf.Error())
+	assert.ErrorIs(t, nf, pgx.ErrNoRows)
+}
@@ -1,12 +1,15 @@
 package testing
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/Mayank85Y/boil/internal/errs"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -98,4 +101,27 @@ func AssertStringContains(t *testing.T, s string, substrings ...string) {
 			fmt.Sprintf("expected string to contain '%s', but it didn't: %s", sub, s),
 		)
 	}
-}
\ No newline at end of file
+}
+
+// AssertRegisteredErrorCode fails the test when err carries an error code missing from the errs catalog
+func AssertRegisteredErrorCode(t *testing.T, err error) {
+	t.Helper()
+
+	var httpErr *errs.HTTPError
+	require.True(t, errors.As(err, &httpErr), "expected an *errs.HTTPError, got %T", err)
+	assert.True(t, errs.IsRegisteredCode(httpErr.Code),
+		fmt.Sprintf("error code %s is not registered in the errs catalog", httpErr.Code))
+}
+
+// AssertRegisteredErrorResponse fails the test when an error response body uses an unregistered code
+func AssertRegisteredErrorResponse(t *testing.T, body []byte) {
+	t.Helper()
+
+	var response struct {
+		Code string `json:"code"`
+	}
+	require.NoError(t, json.Unmarshal(body, &response), "failed to unmarshal error response")
+	require.NotEmpty(t, response.Code, "error response has no code")
+	assert.True(t, errs.IsRegisteredCode(response.Code),
+		fmt.Sprintf("error code %s is not registered in the errs catalog", response.Code))
+}
@@ -0,0 +1,223 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Mayank85Y/boil/internal/database"
+	"github.com/Mayank85Y/boil/internal/lib/lock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// SetupTestRedis starts a Redis container and returns a connected client, the test is skipped without Docker
+func SetupTestRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx := context.Background()
+
+	req := testcontainers.ContainerRequest{
+		Image:        "redis:7-alpine",
+		ExposedPorts: []string{"6379/tcp"},
+		WaitingFor:   wait.ForLog("Ready to accept connections").WithStartupTimeout(30 * time.Second),
+	}
+
+	redisContainer, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	require.NoError(t, err, "failed to start redis container")
+
+	t.Cleanup(func() {
+		if err := redisContainer.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate container: %v", err)
+		}
+	})
+
+	endpoint, err := redisContainer.Endpoint(ctx, "")
+	require.NoError(t, err, "failed to get redis endpoint")
+
+	client := redis.NewClient(&redis.Options{Addr: endpoint})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	require.NoError(t, client.Ping(ctx).Err(), "failed to ping redis")
+
+	return client
+}
+
+// NewTestPostgresLocker builds a lock.PostgresLocker over the test database
+func NewTestPostgresLocker(db *TestDB) *lock.PostgresLocker {
+	logger := zerolog.Nop()
+	return lock.NewPostgresLocker(&database.Database{Pool: db.Pool}, &logger, testLockOptions())
+}
+
+// NewTestRedisLocker builds a lock.RedisLocker over the test redis client
+func NewTestRedisLocker(client *redis.Client) *lock.RedisLocker {
+	return lock.NewRedisLocker(client, testLockOptions())
+}
+
+func testLockOptions() lock.Options {
+	opts := lock.DefaultOptions()
+	opts.RetryInterval = 10 * time.Millisecond
+	return opts
+}
+
+// RunLockerSuite exercises the behaviour every lock.Locker implementation must share.
+// Call it once per backend, e.g.
+//
+//	testDB, _ := SetupTestDB(t)
+//	RunLockerSuite(t, NewTestPostgresLocker(testDB))
+//	RunLockerSuite(t, NewTestRedisLocker(SetupTestRedis(t)))
+func RunLockerSuite(t *testing.T, locker lock.Locker) {
+	t.Helper()
+
+	newKey := func() string {
+		return "test-" + uuid.New().String()
+	}
+
+	t.Run("acquire and release", func(t *testing.T) {
+		ctx := context.Background()
+		key := newKey()
+
+		l, err := locker.Acquire(ctx, key, time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, key, l.Key())
+
+		_, err = locker.TryAcquire(ctx, key, time.Second)
+		require.ErrorIs(t, err, lock.ErrNotAcquired)
+
+		require.NoError(t, l.Release(ctx))
+		require.ErrorIs(t, l.Release(ctx), lock.ErrNotHeld)
+
+		l2, err := locker.TryAcquire(ctx, key, time.Second)
+		require.NoError(t, err)
+		require.NoError(t, l2.Release(ctx))
+	})
+
+	t.Run("fencing tokens increase", func(t *testing.T) {
+		ctx := context.Background()
+		key := newKey()
+
+		first, err := locker.Acquire(ctx, key, time.Second)
+		require.NoError(t, err)
+		require.NoError(t, first.Release(ctx))
+
+		second, err := locker.Acquire(ctx, key, time.Second)
+		require.NoError(t, err)
+		defer second.Release(ctx)
+
+		assert.Greater(t, second.Token(), first.Token())
+	})
+
+	t.Run("acquire times out", func(t *testing.T) {
+		ctx := context.Background()
+		key := newKey()
+
+		held, err := locker.Acquire(ctx, key, 5*time.Second)
+		require.NoError(t, err)
+		defer held.Release(ctx)
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err = locker.Acquire(timeoutCtx, key, time.Second)
+		require.ErrorIs(t, err, lock.ErrNotAcquired)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("expires after ttl", func(t *testing.T) {
+		ctx := context.Background()
+		key := newKey()
+
+		expired, err := locker.Acquire(ctx, key, 200*time.Millisecond)
+		require.NoError(t, err)
+
+		waitCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		defer cancel()
+
+		next, err := locker.Acquire(waitCtx, key, time.Second)
+		require.NoError(t, err)
+		defer next.Release(ctx)
+
+		require.ErrorIs(t, expired.Refresh(ctx, time.Second), lock.ErrNotHeld)
+		require.ErrorIs(t, expired.Release(ctx), lock.ErrNotHeld)
+	})
+
+	t.Run("refresh extends ttl", func(t *testing.T) {
+		ctx := context.Background()
+		key := newKey()
+
+		l, err := locker.Acquire(ctx, key, 200*time.Millisecond)
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			time.Sleep(100 * time.Millisecond)
+			require.NoError(t, l.Refresh(ctx, 200*time.Millisecond))
+		}
+
+		_, err = locker.TryAcquire(ctx, key, time.Second)
+		require.ErrorIs(t, err, lock.ErrNotAcquired)
+		require.NoError(t, l.Release(ctx))
+	})
+
+	t.Run("mutual exclusion", func(t *testing.T) {
+		ctx := context.Background()
+		key := newKey()
+
+		var inside, maxInside, runs atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := lock.WithLock(ctx, locker, key, time.Second, func(ctx context.Context) error {
+					n := inside.Add(1)
+					for {
+						m := maxInside.Load()
+						if n <= m || maxInside.CompareAndSwap(m, n) {
+							break
+						}
+					}
+					time.Sleep(20 * time.Millisecond)
+					inside.Add(-1)
+					runs.Add(1)
+					return nil
+				})
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(5), runs.Load())
+		assert.Equal(t, int32(1), maxInside.Load())
+	})
+
+	t.Run("with lock returns fn error and releases", func(t *testing.T) {
+		ctx := context.Background()
+		key := newKey()
+		boom := errors.New("boom")
+
+		err := lock.WithLock(ctx, locker, key, time.Second, func(ctx context.Context) error {
+			return boom
+		})
+		require.ErrorIs(t, err, boom)
+
+		l, err := locker.TryAcquire(ctx, key, time.Second)
+		require.NoError(t, err)
+		require.NoError(t, l.Release(ctx))
+	})
+}
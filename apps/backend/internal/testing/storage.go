@@ -0,0 +1,228 @@
+package testing
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/Mayank85Y/boil/internal/lib/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	testS3AccessKey = "boil-test"
+	testS3SecretKey = "boil-test-secret"
+)
+
+// SetupTestS3 starts a MinIO container standing in for S3 and returns a storage on a fresh bucket,
+// the test is skipped without Docker
+func SetupTestS3(t *testing.T) *storage.S3Storage {
+	t.Helper()
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx := context.Background()
+
+	req := testcontainers.ContainerRequest{
+		Image:        "minio/minio:RELEASE.2024-10-13T13-34-11Z",
+		ExposedPorts: []string{"9000/tcp"},
+		Cmd:          []string{"server", "/data"},
+		Env: map[string]string{
+			"MINIO_ROOT_USER":     testS3AccessKey,
+			"MINIO_ROOT_PASSWORD": testS3SecretKey,
+		},
+		WaitingFor: wait.ForHTTP("/minio/health/ready").WithPort("9000/tcp").WithStartupTimeout(60 * time.Second),
+	}
+
+	minioContainer, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	require.NoError(t, err, "failed to start minio container")
+
+	t.Cleanup(func() {
+		if err := minioContainer.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate container: %v", err)
+		}
+	})
+
+	endpoint, err := minioContainer.Endpoint(ctx, "")
+	require.NoError(t, err, "failed to get minio endpoint")
+
+	store, err := storage.NewS3Storage(ctx, storage.S3Options{
+		Endpoint:        endpoint,
+		Region:          "us-east-1",
+		Bucket:          "test-" + uuid.New().String()[:8],
+		AccessKeyID:     testS3AccessKey,
+		SecretAccessKey: testS3SecretKey,
+	})
+	require.NoError(t, err, "failed to create s3 storage")
+
+	return store
+}
+
+// NewTestLocalStorage builds a storage.LocalStorage in a directory removed after the test
+func NewTestLocalStorage(t *testing.T) *storage.LocalStorage {
+	t.Helper()
+
+	store, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err, "failed to create local storage")
+	return store
+}
+
+// RunStorageSuite exercises the behaviour every storage.Storage implementation must share.
+// Call it once per backend, e.g.
+//
+//	RunStorageSuite(t, NewTestLocalStorage(t))
+//	RunStorageSuite(t, SetupTestS3(t))
+func RunStorageSuite(t *testing.T, store storage.Storage) {
+	t.Helper()
+
+	newKey := func() string {
+		return "test/" + uuid.New().String() + ".txt"
+	}
+
+	t.Run("put, stat and get", func(t *testing.T) {
+		ctx := context.Background()
+		key := newKey()
+		content := []byte("hello storage")
+		sum := sha256.Sum256(content)
+
+		object, err := store.Put(ctx, key, bytes.NewReader(content), storage.PutOptions{ContentType: "text/plain"})
+		require.NoError(t, err)
+		assert.Equal(t, key, object.Key)
+		assert.Equal(t, int64(len(content)), object.Size)
+		assert.Equal(t, hex.EncodeToString(sum[:]), object.Checksum)
+
+		stat, err := store.Stat(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, object.Size, stat.Size)
+		assert.Equal(t, object.Checksum, stat.Checksum)
+		assert.Equal(t, "text/plain", stat.ContentType)
+
+		reader, _, err := store.Get(ctx, key)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		_, err = reader.Seek(6, io.SeekStart)
+		require.NoError(t, err)
+		rest, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "storage", string(rest))
+	})
+
+	t.Run("unknown size", func(t *testing.T) {
+		ctx := context.Background()
+		content := bytes.Repeat([]byte("x"), 64<<10)
+
+		object, err := store.Put(ctx, newKey(), io.MultiReader(bytes.NewReader(content)), storage.PutOptions{Size: -1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(content)), object.Size)
+	})
+
+	t.Run("put replaces", func(t *testing.T) {
+		ctx := context.Background()
+		key := newKey()
+
+		_, err := store.Put(ctx, key, bytes.NewReader([]byte("first")), storage.PutOptions{})
+		require.NoError(t, err)
+		second, err := store.Put(ctx, key, bytes.NewReader([]byte("second")), storage.PutOptions{})
+		require.NoError(t, err)
+
+		stat, err := store.Stat(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, second.Checksum, stat.Checksum)
+		assert.Equal(t, int64(6), stat.Size)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		ctx := context.Background()
+		key := newKey()
+
+		_, err := store.Put(ctx, key, bytes.NewReader([]byte("gone soon")), storage.PutOptions{})
+		require.NoError(t, err)
+		require.NoError(t, store.Delete(ctx, key))
+
+		_, err = store.Stat(ctx, key)
+		require.ErrorIs(t, err, storage.ErrNotFound)
+		_, _, err = store.Get(ctx, key)
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		require.NoError(t, store.Delete(ctx, key), "deleting a missing key is not an error")
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		ctx := context.Background()
+
+		for _, key := range []string{"", "/etc/passwd", "../outside", "a/../../b", `a\b`, ".meta", ".meta/a.txt.json", "./.meta/a"} {
+			_, err := store.Put(ctx, key, bytes.NewReader([]byte("x")), storage.PutOptions{})
+			assert.ErrorIs(t, err, storage.ErrInvalidKey, key)
+			_, err = store.Stat(ctx, key)
+			assert.ErrorIs(t, err, storage.ErrInvalidKey, key)
+			_, _, err = store.Get(ctx, key)
+			assert.ErrorIs(t, err, storage.ErrInvalidKey, key)
+			assert.ErrorIs(t, store.Delete(ctx, key), storage.ErrInvalidKey, key)
+		}
+	})
+
+	t.Run("metadata is not reachable as an object", func(t *testing.T) {
+		ctx := context.Background()
+		key := newKey()
+
+		_, err := store.Put(ctx, key, bytes.NewReader([]byte("content")), storage.PutOptions{})
+		require.NoError(t, err)
+
+		_, _, err = store.Get(ctx, ".meta/"+key+".json")
+		assert.ErrorIs(t, err, storage.ErrInvalidKey)
+	})
+
+	t.Run("failed put leaves nothing behind", func(t *testing.T) {
+		ctx := context.Background()
+		key := newKey()
+		broken := io.MultiReader(bytes.NewReader([]byte("partial")), iotest.ErrReader(errors.New("connection reset")))
+
+		_, err := store.Put(ctx, key, broken, storage.PutOptions{})
+		require.Error(t, err)
+
+		_, err = store.Stat(ctx, key)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("failed put keeps the previous object", func(t *testing.T) {
+		ctx := context.Background()
+		key := newKey()
+
+		first, err := store.Put(ctx, key, bytes.NewReader([]byte("first")), storage.PutOptions{ContentType: "text/plain"})
+		require.NoError(t, err)
+
+		broken := io.MultiReader(bytes.NewReader([]byte("partial")), iotest.ErrReader(errors.New("connection reset")))
+		_, err = store.Put(ctx, key, broken, storage.PutOptions{})
+		require.Error(t, err)
+
+		reader, stat, err := store.Get(ctx, key)
+		require.NoError(t, err)
+		defer reader.Close()
+		content, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "first", string(content))
+		assert.Equal(t, first.Checksum, stat.Checksum)
+		assert.Equal(t, "text/plain", stat.ContentType)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := store.Put(ctx, newKey(), bytes.NewReader([]byte("never stored")), storage.PutOptions{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,204 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/labstack/echo/v4"
+)
+
+// DefaultMaxBodyBytes caps request bodies read by BindAndValidate
+const DefaultMaxBodyBytes int64 = 1 << 20
+
+// MaxBodyBytesKey holds a per route body limit (int64) that replaces DefaultMaxBodyBytes, see middleware.BodyLimit
+const MaxBodyBytesKey = "max_body_bytes"
+
+// StrictBindKey marks a route (bool) whose bodies are bound with BindOptions.Strict, see middleware.StrictBind
+const StrictBindKey = "strict_bind"
+
+// bodyField is reported for errors that concern the whole body rather than one field
+const bodyField = "body"
+
+// BindOptions controls how request bodies are decoded
+type BindOptions struct {
+	// Strict rejects JSON fields that don't exist on the payload and trailing data after the body
+	Strict bool
+	// MaxBodyBytes rejects larger bodies with 413, 0 disables the limit
+	MaxBodyBytes int64
+}
+
+func DefaultBindOptions() BindOptions {
+	return BindOptions{
+		Strict:       false,
+		MaxBodyBytes: DefaultMaxBodyBytes,
+	}
+}
+
+// bind mirrors echo.DefaultBinder (path params, query for GET/DELETE/HEAD, then body)
+// but decodes JSON itself so decoding failures can be reported per field
+func bind(c echo.Context, payload any, opts BindOptions) error {
+	binder := &echo.DefaultBinder{}
+
+	if err := binder.BindPathParams(c, payload); err != nil {
+		return bindParamError(err)
+	}
+
+	method := c.Request().Method
+	if method == http.MethodGet || method == http.MethodDelete || method == http.MethodHead {
+		if err := binder.BindQueryParams(c, payload); err != nil {
+			return bindParamError(err)
+		}
+	}
+
+	req := c.Request()
+	if req.ContentLength == 0 {
+		return nil
+	}
+
+	if opts.MaxBodyBytes > 0 {
+		req.Body = http.MaxBytesReader(c.Response(), req.Body, opts.MaxBodyBytes)
+	}
+
+	if !strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		if err := binder.BindBody(c, payload); err != nil {
+			return bindBodyError(err, opts)
+		}
+		return nil
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	if opts.Strict {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(payload); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return bindBodyError(err, opts)
+	}
+
+	if opts.Strict {
+		if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+			return errs.NewBadRequestError("Request body is not valid JSON", true, nil, []errs.FieldError{
+				bodyFieldError(bodyField, "must contain a single JSON value", "bind.field.single", nil),
+			}, nil).WithMessageKey("bind.invalid_json", nil)
+		}
+	}
+	return nil
+}
+
+// bindBodyError turns decoder errors into field errors naming the offending JSON path
+func bindBodyError(err error, opts BindOptions) error {
+	var (
+		maxBytesErr *http.MaxBytesError
+		typeErr     *json.UnmarshalTypeError
+		syntaxErr   *json.SyntaxError
+		echoErr     *echo.HTTPError
+	)
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		limit := strconv.FormatInt(maxBytesErr.Limit, 10)
+		return errs.NewRequestTooLargeError(
+			fmt.Sprintf("Request body must not exceed %s bytes", limit), true, maxBytesErr.Limit).
+			WithMessageKey("bind.too_large", map[string]string{"limit": limit}).
+			WithCause(err)
+
+	case errors.As(err, &typeErr):
+		field := typeErr.Field
+		if field == "" {
+			field = bodyField
+		}
+		expected := jsonTypeName(typeErr.Type)
+		return errs.NewBadRequestError("Request body has an invalid type", true, nil, []errs.FieldError{
+			bodyFieldError(field, fmt.Sprintf("must be of type %s, got %s", expected, typeErr.Value),
+				"bind.field.type", map[string]string{"type": expected, "value": typeErr.Value}),
+		}, nil).WithMessageKey("bind.invalid_type", nil).WithCause(err)
+
+	case errors.As(err, &syntaxErr):
+		offset := strconv.FormatInt(syntaxErr.Offset, 10)
+		return errs.NewBadRequestError("Request body is not valid JSON", true, nil, []errs.FieldError{
+			bodyFieldError(bodyField, "is malformed at byte "+offset, "bind.field.syntax", map[string]string{"offset": offset}),
+		}, nil).WithMessageKey("bind.invalid_json", nil).WithCause(err)
+
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return errs.NewBadRequestError("Request body is not valid JSON", true, nil, []errs.FieldError{
+			bodyFieldError(bodyField, "ends unexpectedly", "bind.field.eof", nil),
+		}, nil).WithMessageKey("bind.invalid_json", nil).WithCause(err)
+
+	case opts.Strict && strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no typed error for unknown fields
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return errs.NewBadRequestError("Request body contains unknown fields", true, nil, []errs.FieldError{
+			bodyFieldError(field, "is not allowed", "bind.field.unknown", nil),
+		}, nil).WithMessageKey("bind.unknown_fields", nil).WithCause(err)
+
+	case errors.As(err, &echoErr):
+		if echoErr.Internal != nil {
+			return bindBodyError(echoErr.Internal, opts)
+		}
+		return bindParamError(echoErr)
+	}
+
+	return errs.NewBadRequestError("Request body could not be read", true, nil, nil, nil).
+		WithMessageKey("bind.unreadable", nil).
+		WithCause(err)
+}
+
+func bodyFieldError(field string, message string, key string, params map[string]string) errs.FieldError {
+	return errs.FieldError{
+		Field:    field,
+		Error:    message,
+		Location: errs.FieldLocationBody,
+		Key:      key,
+		Params:   params,
+	}
+}
+
+// bindParamError reports path and query binding failures, echo only gives us a message for those
+func bindParamError(err error) error {
+	message := "Invalid request parameters"
+
+	var echoErr *echo.HTTPError
+	if errors.As(err, &echoErr) {
+		if msg, ok := echoErr.Message.(string); ok && msg != "" {
+			message = msg
+		}
+	}
+	return errs.NewBadRequestError(message, false, nil, nil, nil).WithCause(err)
+}
+
+// jsonTypeName names a Go type the way a JSON client thinks about it
+func jsonTypeName(t reflect.Type) string {
+	if t == nil {
+		return "value"
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return t.String()
+	}
+}
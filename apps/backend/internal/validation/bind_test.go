@@ -0,0 +1,118 @@
+package validation_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/Mayank85Y/boil/internal/validation"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bindOwner struct {
+	Age int `json:"age"`
+}
+
+type bindPayload struct {
+	ID    string    `param:"id"`
+	Name  string    `json:"name"`
+	Owner bindOwner `json:"owner"`
+	Tags  []string  `json:"tags"`
+}
+
+func bindRequest(t *testing.T, method string, target string, body string, opts validation.BindOptions) (*bindPayload, error) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	c.SetParamNames("id")
+	c.SetParamValues("42")
+
+	payload := &bindPayload{}
+	return payload, validation.BindAndValidateWithOptions(c, payload, opts)
+}
+
+func TestBindErrors(t *testing.T) {
+	strict := validation.DefaultBindOptions()
+	strict.Strict = true
+	tiny := validation.DefaultBindOptions()
+	tiny.MaxBodyBytes = 8
+
+	tests := []struct {
+		name   string
+		body   string
+		opts   validation.BindOptions
+		status int
+		key    string
+		field  errs.FieldError // zero when the error names no field
+	}{
+		{"too large", `{"name":"a long name"}`, tiny, http.StatusRequestEntityTooLarge, "bind.too_large", errs.FieldError{}},
+		{"type", `{"owner":{"age":"old"}}`, validation.DefaultBindOptions(), http.StatusBadRequest, "bind.invalid_type",
+			errs.FieldError{Field: "owner.age", Error: "must be of type integer, got string", Location: errs.FieldLocationBody,
+				Key: "bind.field.type", Params: map[string]string{"type": "integer", "value": "string"}}},
+		{"type of body", `[1]`, validation.DefaultBindOptions(), http.StatusBadRequest, "bind.invalid_type",
+			errs.FieldError{Field: "body", Error: "must be of type object, got array", Location: errs.FieldLocationBody,
+				Key: "bind.field.type", Params: map[string]string{"type": "object", "value": "array"}}},
+		{"syntax", `{"name":}`, validation.DefaultBindOptions(), http.StatusBadRequest, "bind.invalid_json",
+			errs.FieldError{Field: "body", Error: "is malformed at byte 9", Location: errs.FieldLocationBody,
+				Key: "bind.field.syntax", Params: map[string]string{"offset": "9"}}},
+		{"eof", `{"name":"ada"`, validation.DefaultBindOptions(), http.StatusBadRequest, "bind.invalid_json",
+			errs.FieldError{Field: "body", Error: "ends unexpectedly", Location: errs.FieldLocationBody, Key: "bind.field.eof"}},
+		{"unknown field", `{"nmae":"ada"}`, strict, http.StatusBadRequest, "bind.unknown_fields",
+			errs.FieldError{Field: "nmae", Error: "is not allowed", Location: errs.FieldLocationBody, Key: "bind.field.unknown"}},
+		{"trailing value", `{"name":"ada"} {}`, strict, http.StatusBadRequest, "bind.invalid_json",
+			errs.FieldError{Field: "body", Error: "must contain a single JSON value", Location: errs.FieldLocationBody, Key: "bind.field.single"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := bindRequest(t, http.MethodPost, "/items/42", tt.body, tt.opts)
+
+			var httpErr *errs.HTTPError
+			require.True(t, errors.As(err, &httpErr), err)
+			assert.Equal(t, tt.status, httpErr.Status)
+			assert.Equal(t, tt.key, httpErr.MessageKey)
+			if tt.field.Field == "" {
+				assert.Empty(t, httpErr.Errors)
+				return
+			}
+			require.Len(t, httpErr.Errors, 1)
+			assert.Equal(t, tt.field, httpErr.Errors[0])
+		})
+	}
+}
+
+func TestBindLenientIgnoresUnknownFields(t *testing.T) {
+	payload, err := bindRequest(t, http.MethodPost, "/items/42", `{"name":"ada","nmae":"typo"} {}`, validation.DefaultBindOptions())
+	require.NoError(t, err)
+	assert.Equal(t, &bindPayload{ID: "42", Name: "ada"}, payload)
+
+	payload, err = bindRequest(t, http.MethodPost, "/items/42", "", validation.DefaultBindOptions())
+	require.NoError(t, err)
+	assert.Equal(t, "42", payload.ID)
+}
+
+func TestBindParamErrors(t *testing.T) {
+	type query struct {
+		Page int `query:"page"`
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/items?page=two", nil)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+
+	err := validation.BindAndValidateWithOptions(c, &query{}, validation.DefaultBindOptions())
+
+	var httpErr *errs.HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusBadRequest, httpErr.Status)
+	assert.NotEmpty(t, httpErr.Message)
+	assert.False(t, httpErr.Override)
+}
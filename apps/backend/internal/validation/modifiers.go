@@ -0,0 +1,205 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ModTag is the struct tag holding comma separated modifiers, e.g. `mod:"trim,lower"`
+const ModTag = "mod"
+
+// Modifier rewrites a string value, param is the part after "=" in the tag (truncate=50)
+type Modifier func(value string, param string) string
+
+var (
+	modifiersMu sync.RWMutex
+	modifiers   = map[string]Modifier{}
+)
+
+func init() {
+	for name, modifier := range builtinModifiers {
+		RegisterModifier(name, modifier)
+	}
+}
+
+// RegisterModifier adds a modifier usable in mod tags. Call it from init, registering a name twice panics.
+func RegisterModifier(name string, modifier Modifier) {
+	if name == "" || modifier == nil {
+		panic("validation: modifier needs a name and a func")
+	}
+
+	modifiersMu.Lock()
+	defer modifiersMu.Unlock()
+	if _, exists := modifiers[name]; exists {
+		panic(fmt.Sprintf("validation: modifier %q registered twice", name))
+	}
+	modifiers[name] = modifier
+}
+
+// Normalize applies mod tags in place, recursing into nested structs, pointers and slices.
+// string, *string and []string fields can carry modifiers.
+func Normalize(payload any) error {
+	v := reflect.ValueOf(payload)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return nil
+	}
+	return normalizeValue(v.Elem())
+}
+
+func normalizeValue(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return normalizeValue(v.Elem())
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := normalizeValue(v.Index(i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			fieldValue := v.Field(i)
+			if tag, ok := field.Tag.Lookup(ModTag); ok && tag != "" && tag != "-" {
+				if err := applyModifiers(fieldValue, tag); err != nil {
+					return fmt.Errorf("validation: field %s: %w", field.Name, err)
+				}
+				continue
+			}
+			if err := normalizeValue(fieldValue); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyModifiers runs every modifier of the tag on a string, *string or []string field
+func applyModifiers(v reflect.Value, tag string) error {
+	switch v.Kind() {
+	case reflect.String:
+		if !v.CanSet() {
+			return nil
+		}
+		value := v.String()
+		for _, spec := range strings.Split(tag, ",") {
+			name, param, _ := strings.Cut(strings.TrimSpace(spec), "=")
+
+			modifiersMu.RLock()
+			modifier, ok := modifiers[name]
+			modifiersMu.RUnlock()
+			if !ok {
+				return fmt.Errorf("unknown modifier %q", name)
+			}
+			value = modifier(value, param)
+		}
+		v.SetString(value)
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return applyModifiers(v.Elem(), tag)
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := applyModifiers(v.Index(i), tag); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fmt.Errorf("mod tag on unsupported kind %s", v.Kind())
+	}
+	return nil
+}
+
+var builtinModifiers = map[string]Modifier{
+	"trim": func(value string, param string) string {
+		return strings.TrimSpace(value)
+	},
+	"ltrim": func(value string, param string) string {
+		return strings.TrimLeftFunc(value, unicode.IsSpace)
+	},
+	"rtrim": func(value string, param string) string {
+		return strings.TrimRightFunc(value, unicode.IsSpace)
+	},
+	"lower": func(value string, param string) string {
+		return strings.ToLower(value)
+	},
+	"upper": func(value string, param string) string {
+		return strings.ToUpper(value)
+	},
+	"title": func(value string, param string) string {
+		return cases.Title(language.English).String(value)
+	},
+	// squish trims and collapses runs of whitespace into single spaces
+	"squish": func(value string, param string) string {
+		return strings.Join(strings.Fields(value), " ")
+	},
+	// nfc composes unicode so "é" typed two different ways compares equal, nfkc also folds compatibility forms
+	"nfc": func(value string, param string) string {
+		return norm.NFC.String(value)
+	},
+	"nfkc": func(value string, param string) string {
+		return norm.NFKC.String(value)
+	},
+	"truncate": func(value string, param string) string {
+		limit, err := strconv.Atoi(param)
+		if err != nil || limit < 0 {
+			return value
+		}
+		if runes := []rune(value); len(runes) > limit {
+			return string(runes[:limit])
+		}
+		return value
+	},
+	"e164": normalizeE164,
+}
+
+// normalizeE164 strips formatting from phone numbers ("+49 (30) 123-45" -> "+493012345").
+// A leading 00 becomes +, numbers without a country code are left for the e164 validator to reject.
+func normalizeE164(value string, param string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return value
+	}
+
+	var b strings.Builder
+	for i, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case r == ' ', r == '-', r == '.', r == '(', r == ')', r == '/':
+			// formatting
+		default:
+			return value
+		}
+	}
+
+	normalized := b.String()
+	if strings.HasPrefix(normalized, "00") {
+		normalized = "+" + normalized[2:]
+	}
+	return normalized
+}
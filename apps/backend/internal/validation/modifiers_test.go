@@ -0,0 +1,135 @@
+package validation_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Mayank85Y/boil/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	validation.RegisterModifier("reverse", func(value string, param string) string {
+		runes := []rune(value)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes)
+	})
+}
+
+func TestModifiers(t *testing.T) {
+	tests := []struct {
+		tag   string
+		value string
+		want  string
+	}{
+		{"trim", "  ada  ", "ada"},
+		{"ltrim", "  ada  ", "ada  "},
+		{"rtrim", "  ada  ", "  ada"},
+		{"lower", "ADA", "ada"},
+		{"upper", "ada", "ADA"},
+		{"title", "ada lovelace", "Ada Lovelace"},
+		{"squish", "  ada \t lovelace\n", "ada lovelace"},
+		{"nfc", "é", "é"},
+		{"nfkc", "Ａ", "A"},
+		{"truncate=3", "lovelace", "lov"},
+		{"truncate=3", "äöüß", "äöü"},
+		{"truncate=x", "lovelace", "lovelace"},
+		{"e164", "+49 (30) 123-45", "+493012345"},
+		{"e164", "0049 30 12345", "+493012345"},
+		{"e164", "call me", "call me"},
+		{"trim,lower", "  ADA ", "ada"},
+		{" trim , reverse ", " ada ", "ada"},
+		{"reverse", "abc", "cba"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag+" "+tt.value, func(t *testing.T) {
+			got, err := normalizeWith(tt.tag, tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// normalizeWith applies tag to value through a struct field, mod tags only live on fields
+func normalizeWith(tag string, value string) (string, error) {
+	t := reflect.StructOf([]reflect.StructField{
+		{Name: "Value", Type: reflect.TypeOf(""), Tag: reflect.StructTag(`mod:"` + tag + `"`)},
+	})
+	payload := reflect.New(t)
+	payload.Elem().Field(0).SetString(value)
+	err := validation.Normalize(payload.Interface())
+	return payload.Elem().Field(0).String(), err
+}
+
+type modNested struct {
+	Name string `mod:"trim"`
+}
+
+type modPayload struct {
+	Email    string   `mod:"trim,lower"`
+	Nickname *string  `mod:"trim"`
+	Tags     []string `mod:"lower"`
+	Nested   modNested
+	Pointer  *modNested
+	Items    []modNested
+	Skipped  string  `mod:"-"`
+	Missing  *string `mod:"trim"`
+	Any      any
+	private  string `mod:"trim"`
+}
+
+func TestNormalizeWalksPayload(t *testing.T) {
+	nickname := "  ada "
+	payload := &modPayload{
+		Email:    "  Ada@Example.COM ",
+		Nickname: &nickname,
+		Tags:     []string{"Go", "SQL"},
+		Nested:   modNested{Name: " a "},
+		Pointer:  &modNested{Name: " b "},
+		Items:    []modNested{{Name: " c "}},
+		Skipped:  " d ",
+		Any:      &modNested{Name: " e "},
+		private:  " f ",
+	}
+	require.NoError(t, validation.Normalize(payload))
+
+	assert.Equal(t, "ada@example.com", payload.Email)
+	assert.Equal(t, "ada", *payload.Nickname)
+	assert.Equal(t, []string{"go", "sql"}, payload.Tags)
+	assert.Equal(t, "a", payload.Nested.Name)
+	assert.Equal(t, "b", payload.Pointer.Name)
+	assert.Equal(t, "c", payload.Items[0].Name)
+	assert.Equal(t, " d ", payload.Skipped)
+	assert.Nil(t, payload.Missing)
+	assert.Equal(t, "e", payload.Any.(*modNested).Name)
+	assert.Equal(t, " f ", payload.private)
+
+	assert.NoError(t, validation.Normalize(modPayload{}))
+	assert.NoError(t, validation.Normalize((*modPayload)(nil)))
+}
+
+func TestNormalizeRejectsBadTags(t *testing.T) {
+	unknown := &struct {
+		Name string `mod:"trim,shout"`
+	}{}
+	err := validation.Normalize(unknown)
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), `unknown modifier "shout"`), err)
+
+	unsupported := &struct {
+		Age int `mod:"trim"`
+	}{}
+	assert.ErrorContains(t, validation.Normalize(unsupported), "unsupported kind int")
+}
+
+func TestRegisterModifierPanics(t *testing.T) {
+	noop := func(value string, param string) string { return value }
+	assert.Panics(t, func() { validation.RegisterModifier("trim", noop) })
+	assert.Panics(t, func() { validation.RegisterModifier("", noop) })
+	assert.Panics(t, func() { validation.RegisterModifier("nil", nil) })
+}
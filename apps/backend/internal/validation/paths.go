@@ -0,0 +1,136 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/go-playground/validator/v10"
+)
+
+// bindingTags are checked in order to find the name a client uses for a field,
+// path and query win over json because a field bound from the URL never comes from the body
+var bindingTags = []struct {
+	tag      string
+	location string
+}{
+	{"param", errs.FieldLocationPath},
+	{"query", errs.FieldLocationQuery},
+	{"json", errs.FieldLocationBody},
+	{"form", errs.FieldLocationBody},
+}
+
+func init() {
+	validate.RegisterTagNameFunc(clientFieldName)
+}
+
+// clientFieldName names struct fields after their binding tag so FieldError paths match the request
+func clientFieldName(field reflect.StructField) string {
+	name, _ := bindingTag(field)
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+func bindingTag(field reflect.StructField) (string, string) {
+	for _, bt := range bindingTags {
+		if value, ok := field.Tag.Lookup(bt.tag); ok {
+			if name, _, _ := strings.Cut(value, ","); name != "" {
+				return name, bt.location
+			}
+		}
+	}
+	return field.Name, errs.FieldLocationBody
+}
+
+// fieldPath drops the root struct name from the namespace, e.g. CreateOrder.items[2].address.zip -> items[2].address.zip.
+// Embedded structs are flattened like encoding/json and echo's binder do, ListTodos.PageQuery.limit -> limit.
+func fieldPath(payload any, fe validator.FieldError) string {
+	segments := clientSegments(payload, fe)
+	if len(segments) == 0 {
+		if _, path, ok := strings.Cut(fe.Namespace(), "."); ok {
+			return path
+		}
+		return fe.Field()
+	}
+	names := make([]string, len(segments))
+	for i, segment := range segments {
+		names[i] = segment.name
+	}
+	return strings.Join(names, ".")
+}
+
+// fieldLocation finds where the top level field of a failed path was bound from
+func fieldLocation(payload any, fe validator.FieldError) string {
+	segments := clientSegments(payload, fe)
+	if len(segments) == 0 || segments[0].field == nil {
+		return errs.FieldLocationBody
+	}
+	_, location := bindingTag(*segments[0].field)
+	return location
+}
+
+type pathSegment struct {
+	name  string               // as the client sent it, e.g. items[2]
+	field *reflect.StructField // nil when the type could not be followed
+}
+
+// clientSegments walks the namespace (client names) and the struct namespace (Go names) side by side
+// through the payload type, segments of untagged embedded structs are left out
+func clientSegments(payload any, fe validator.FieldError) []pathSegment {
+	names := strings.Split(fe.Namespace(), ".")
+	goNames := strings.Split(fe.StructNamespace(), ".")
+	if len(names) < 2 || len(names) != len(goNames) {
+		return nil
+	}
+
+	t := structType(reflect.TypeOf(payload))
+	segments := make([]pathSegment, 0, len(names)-1)
+	for i := 1; i < len(names); i++ {
+		goName, _, _ := strings.Cut(goNames[i], "[")
+
+		var field *reflect.StructField
+		if t != nil {
+			if f, ok := t.FieldByName(goName); ok {
+				field = &f
+			}
+		}
+		if field == nil {
+			segments = append(segments, pathSegment{name: names[i]})
+			t = nil
+			continue
+		}
+
+		t = structType(field.Type)
+		if field.Anonymous && !hasBindingTag(*field) {
+			continue
+		}
+		segments = append(segments, pathSegment{name: names[i], field: field})
+	}
+	return segments
+}
+
+func hasBindingTag(field reflect.StructField) bool {
+	for _, bt := range bindingTags {
+		if _, ok := field.Tag.Lookup(bt.tag); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// structType follows pointers, slices and maps down to a struct, nil when there is none
+func structType(t reflect.Type) reflect.Type {
+	for t != nil {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		case reflect.Struct:
+			return t
+		default:
+			return nil
+		}
+	}
+	return nil
+}
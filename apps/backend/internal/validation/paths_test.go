@@ -0,0 +1,115 @@
+package validation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/Mayank85Y/boil/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pathAddress struct {
+	Zip string `json:"zip" validate:"required"`
+}
+
+type pathItem struct {
+	SKU     string       `json:"sku" validate:"required"`
+	Address *pathAddress `json:"address"`
+}
+
+type pathPage struct {
+	Limit int `query:"limit" validate:"omitempty,max=10"`
+}
+
+type pathPayload struct {
+	pathPage
+	OrderID string     `param:"orderId" validate:"required"`
+	Search  string     `query:"q" validate:"omitempty,min=3"`
+	Items   []pathItem `json:"items" validate:"dive"`
+	Note    string     `json:"note,omitempty" form:"comment" validate:"omitempty,max=5"`
+	Comment string     `form:"comment" validate:"omitempty,max=5"`
+	Secret  string     `json:"-" validate:"omitempty,max=1"`
+}
+
+func fieldErrors(t *testing.T, payload any) map[string]errs.FieldError {
+	t.Helper()
+
+	var httpErr *errs.HTTPError
+	require.True(t, errors.As(validation.Validate(context.Background(), payload), &httpErr))
+	assert.Equal(t, "validation.failed", httpErr.MessageKey)
+
+	byField := map[string]errs.FieldError{}
+	for _, fe := range httpErr.Errors {
+		byField[fe.Field] = fe
+	}
+	return byField
+}
+
+func TestFieldPathsAndLocations(t *testing.T) {
+	got := fieldErrors(t, &pathPayload{
+		pathPage: pathPage{Limit: 11},
+		Search:   "ab",
+		Items:    []pathItem{{SKU: "a"}, {Address: &pathAddress{}}},
+		Note:     "too long",
+		Comment:  "too long",
+		Secret:   "xx",
+	})
+
+	tests := []struct {
+		field    string
+		location string
+	}{
+		{"limit", errs.FieldLocationQuery}, // embedded structs are flattened
+		{"orderId", errs.FieldLocationPath},
+		{"q", errs.FieldLocationQuery},
+		{"items[1].sku", errs.FieldLocationBody},
+		{"items[1].address.zip", errs.FieldLocationBody},
+		{"note", errs.FieldLocationBody}, // json wins over form
+		{"comment", errs.FieldLocationBody},
+	}
+
+	for _, tt := range tests {
+		fe, ok := got[tt.field]
+		if assert.True(t, ok, "missing %s in %v", tt.field, got) {
+			assert.Equal(t, tt.location, fe.Location, tt.field)
+		}
+	}
+	// json:"-" fields keep their Go name
+	assert.Contains(t, got, "Secret")
+	assert.Len(t, got, len(tests)+1)
+}
+
+type hookPayload struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email"`
+}
+
+func (p *hookPayload) Validate(ctx context.Context) error {
+	var problems validation.CustomValidationErrors
+	if p.Email == "" {
+		problems.Add("email", "is required")
+	}
+	return problems.OrNil()
+}
+
+func TestValidateMergesHookErrors(t *testing.T) {
+	got := fieldErrors(t, &hookPayload{})
+	assert.Equal(t, "is required", got["name"].Error)
+	assert.Equal(t, errs.FieldLocationBody, got["name"].Location)
+	assert.Equal(t, "is required", got["email"].Error)
+	assert.Len(t, got, 2)
+
+	assert.NoError(t, validation.Validate(context.Background(), &hookPayload{Name: "ada", Email: "ada@example.com"}))
+}
+
+type failingHook struct{}
+
+func (failingHook) Validate(ctx context.Context) error { return errors.New("database down") }
+
+func TestValidatePassesOtherHookErrorsOn(t *testing.T) {
+	err := validation.Validate(context.Background(), failingHook{})
+	assert.EqualError(t, err, "database down")
+}
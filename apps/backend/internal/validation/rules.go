@@ -0,0 +1,126 @@
+package validation
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// MinPasswordLength is the shortest password accepted by strong_password
+const MinPasswordLength = 8
+
+var slugRegex = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+var builtinRules = []Rule{
+	{
+		Tag:     "uuidList",
+		Func:    isUUIDList,
+		Message: Message("must be a comma-separated list of valid UUIDs"),
+	},
+	{
+		Tag:     "slug",
+		Func:    isSlug,
+		Message: Message("must contain only lowercase letters, numbers and single dashes"),
+	},
+	{
+		Tag:     "timezone",
+		Func:    isTimezone,
+		Message: Message("must be a valid IANA time zone such as Europe/Berlin"),
+	},
+	{
+		Tag:  "strong_password",
+		Func: isStrongPassword,
+		Message: Message("must be at least 8 characters and contain an uppercase letter, " +
+			"a lowercase letter, a number and a symbol"),
+	},
+	{
+		Tag:     "country",
+		Func:    isCountry,
+		Message: Message("must be a valid ISO 3166-1 alpha-2 country code"),
+	},
+	{
+		Tag:     "future_date",
+		Func:    isFutureDate,
+		Message: Message("must be in the future"),
+	},
+}
+
+func isUUIDList(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if value == "" {
+		return true
+	}
+	for _, id := range strings.Split(value, ",") {
+		if !IsValidUUID(strings.TrimSpace(id)) {
+			return false
+		}
+	}
+	return true
+}
+
+func isSlug(fl validator.FieldLevel) bool {
+	return slugRegex.MatchString(fl.Field().String())
+}
+
+func isTimezone(fl validator.FieldLevel) bool {
+	name := fl.Field().String()
+	// time.LoadLocation treats "" and "Local" as the server zone, clients must name one
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+func isStrongPassword(fl validator.FieldLevel) bool {
+	password := fl.Field().String()
+	if len([]rune(password)) < MinPasswordLength {
+		return false
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r), unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+	return upper && lower && digit && symbol
+}
+
+// isCountry accepts ISO 3166-1 alpha-2 codes in any case, e.g. "de" and "DE"
+func isCountry(fl validator.FieldLevel) bool {
+	return validate.Var(strings.ToUpper(fl.Field().String()), "iso3166_1_alpha2") == nil
+}
+
+// isFutureDate accepts time.Time as well as RFC 3339 and YYYY-MM-DD strings
+func isFutureDate(fl validator.FieldLevel) bool {
+	field := fl.Field()
+
+	if field.Type() == reflect.TypeOf(time.Time{}) {
+		return field.Interface().(time.Time).After(time.Now())
+	}
+	if field.Kind() != reflect.String {
+		return false
+	}
+
+	value := field.String()
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.After(time.Now())
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		// a date is in the future when it starts after today
+		return t.After(time.Now().UTC().Truncate(24 * time.Hour))
+	}
+	return false
+}
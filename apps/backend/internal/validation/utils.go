@@ -1,20 +1,27 @@
 package validation
 
 import (
-	"fmt"
-	"reflect"
+	"context"
+	"errors"
 	"regexp"
-	"strings"
 
 	"github.com/Mayank85Y/boil/internal/errs"
+	"github.com/Mayank85Y/boil/internal/i18n"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 )
 
+//Validatable is the legacy hook, prefer ContextValidatable. Struct tags are validated before either hook runs.
 type Validatable interface {
 	Validate() error
 }
 
+//ContextValidatable is an optional hook for cross-field and async rules e.g. uniqueness checks through
+//repositories. Return CustomValidationErrors for client mistakes, any other error is passed on as is.
+type ContextValidatable interface {
+	Validate(ctx context.Context) error
+}
+
 type CustomValidationError struct {
 	Field   string
 	Message string
@@ -26,30 +33,111 @@ func (c CustomValidationErrors) Error() string {
 	return "Validation failed"
 }
 
-func BindAndValidate(c echo.Context, payload Validatable) error{
-	//Bind() help in seearlization as data recievied in bytes
-	if err := c.Bind(payload); err != nil {
-		message := strings.Split(strings.Split(err.Error(), ",")[1], "message")[1]
-		return errs.NewBadRequestError(message, false, nil, nil, nil)
+func (c *CustomValidationErrors) Add(field string, message string) {
+	*c = append(*c, CustomValidationError{Field: field, Message: message})
+}
+
+//nil when nothing was added so Validate(ctx) can end with `return problems.OrNil()`
+func (c CustomValidationErrors) OrNil() error {
+	if len(c) == 0 {
+		return nil
+	}
+	return c
+}
+
+func BindAndValidate(c echo.Context, payload any) error{
+	opts := DefaultBindOptions()
+	if limit, ok := c.Get(MaxBodyBytesKey).(int64); ok {
+		opts.MaxBodyBytes = limit
+	}
+	if strict, ok := c.Get(StrictBindKey).(bool); ok {
+		opts.Strict = strict
+	}
+	return BindAndValidateWithOptions(c, payload, opts)
+}
+
+func BindAndValidateWithOptions(c echo.Context, payload any, opts BindOptions) error{
+	//bind help in seearlization as data recievied in bytes, see bind.go
+	if err := bind(c, payload, opts); err != nil {
+		return err
 	}
-	if msg, fieldErrors := validateStruct(payload); fieldErrors != nil {
-		return errs.NewBadRequestError(msg, true, nil, fieldErrors, nil)
+	//mod tags run first so validation and handlers see the same cleaned values, see modifiers.go
+	if err := Normalize(payload); err != nil {
+		return err
+	}
+	//the locale travels in the context so Validate(ctx) hooks can translate their messages too
+	ctx := i18n.WithLocale(c.Request().Context(), i18n.FromEcho(c))
+	c.SetRequest(c.Request().WithContext(ctx))
+	return Validate(ctx, payload)
+}
+
+//Validate runs struct tags and then the optional Validate hook, field errors from both end up in one response
+func Validate(ctx context.Context, payload any) error {
+	var fieldErrors []errs.FieldError
+
+	if err := ValidateStruct(payload); err != nil {
+		var invalidErr *validator.InvalidValidationError
+		if !errors.As(err, &invalidErr) {
+			fieldErrors = append(fieldErrors, extractValidationErrors(ctx, payload, err)...)
+		}
+	}
+
+	var hookErr error
+	switch v := payload.(type) {
+	case ContextValidatable:
+		hookErr = v.Validate(ctx)
+	case Validatable:
+		hookErr = v.Validate()
+	}
+	if hookErr != nil {
+		if !isValidationError(hookErr) {
+			return hookErr
+		}
+		fieldErrors = mergeFieldErrors(fieldErrors, extractValidationErrors(ctx, payload, hookErr))
+	}
+
+	if len(fieldErrors) > 0 {
+		return errs.NewBadRequestError("Validation failed", true, nil, fieldErrors, nil).
+			WithMessageKey("validation.failed", nil)
 	}
 	return nil
 }
 
-func validateStruct(v Validatable) (string, []errs.FieldError) {
-	if err := v.Validate(); err != nil {
-		return extractValidationErrors(err)
+func isValidationError(err error) bool {
+	var validationErrors validator.ValidationErrors
+	var customErrors CustomValidationErrors
+	return errors.As(err, &validationErrors) || errors.As(err, &customErrors)
+}
+
+//hooks written before tags ran automatically may validate the tags again, skip those duplicates
+func mergeFieldErrors(existing []errs.FieldError, extra []errs.FieldError) []errs.FieldError {
+	type fieldErrorKey struct{ field, error, location string }
+
+	seen := make(map[fieldErrorKey]bool, len(existing))
+	for _, fe := range existing {
+		seen[fieldErrorKey{fe.Field, fe.Error, fe.Location}] = true
 	}
-	return "", nil
+	for _, fe := range extra {
+		key := fieldErrorKey{fe.Field, fe.Error, fe.Location}
+		if !seen[key] {
+			seen[key] = true
+			existing = append(existing, fe)
+		}
+	}
+	return existing
 }
 
-func extractValidationErrors(err error) (string, []errs.FieldError) {
+//payload is used to tell where each failed field was bound from
+func extractValidationErrors(ctx context.Context, payload any, err error) []errs.FieldError {
 	var fieldErrors []errs.FieldError
-	validationErrors, ok := err.(validator.ValidationErrors)
-	if !ok {
-		customValidationErrors := err.(CustomValidationErrors)
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		var customValidationErrors CustomValidationErrors
+		if !errors.As(err, &customValidationErrors) {
+			return []errs.FieldError{
+				{Field: bodyField, Error: err.Error(), Location: errs.FieldLocationBody},
+			}
+		}
 		for _, err := range customValidationErrors {
 			fieldErrors = append(fieldErrors, errs.FieldError{
 				Field: err.Field,
@@ -58,52 +146,16 @@ func extractValidationErrors(err error) (string, []errs.FieldError) {
 		}
 	}
 
+	//messages come from the tag registry, see validator.go
 	for _, err := range validationErrors {
-		field := strings.ToLower(err.Field())
-		var msg string
-
-		switch err.Tag() {
-		case "required":
-			msg = "is required"
-		case "min":
-			if err.Type().Kind() == reflect.String {
-				msg = fmt.Sprintf("must be at least %s characters", err.Param())
-			} else {
-				msg = fmt.Sprintf("must be at least %s", err.Param())
-			}
-		case "max":
-			if err.Type().Kind() == reflect.String {
-				msg = fmt.Sprintf("must not exceed %s characters", err.Param())
-			} else {
-				msg = fmt.Sprintf("must not exceed %s", err.Param())
-			}
-		case "oneof":
-			msg = fmt.Sprintf("must be one of: %s", err.Param())
-		case "email":
-			msg = "must be a valid email address"
-		case "e164":
-			msg = "must be a valid phone number with country code"
-		case "uuid":
-			msg = "must be a valid UUID"
-		case "uuidList":
-			msg = "must be a comma-separated list of valid UUIDs"
-		case "dive":
-			msg = "some items are invalid"
-		default:
-			if err.Param() != "" {
-				msg = fmt.Sprintf("%s: %s:%s", field, err.Tag(), err.Param())
-			} else {
-				msg = fmt.Sprintf("%s: %s", field, err.Tag())
-			}
-		}
-
 		fieldErrors = append(fieldErrors, errs.FieldError{
-			Field: strings.ToLower(err.Field()),
-			Error: msg,
+			Field:    fieldPath(payload, err),
+			Error:    messageFor(i18n.FromContext(ctx), err),
+			Location: fieldLocation(payload, err),
 		})
 	}
 
-	return "Validation failed", fieldErrors
+	return fieldErrors
 }
 
 var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
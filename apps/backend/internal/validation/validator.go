@@ -0,0 +1,166 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/Mayank85Y/boil/internal/i18n"
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/text/language"
+)
+
+// MessageFunc renders the client facing message for a failed tag, e.g. "must be at least 3 characters"
+type MessageFunc func(fe validator.FieldError) string
+
+// Rule is a custom validation tag together with the message reported when it fails
+type Rule struct {
+	Tag     string
+	Func    validator.Func
+	Message MessageFunc
+	// CallValidationEvenIfNull runs Func for nil pointers too, see validator.RegisterValidation
+	CallValidationEvenIfNull bool
+}
+
+var (
+	validate = validator.New(validator.WithRequiredStructEnabled())
+
+	messagesMu sync.RWMutex
+	messages   = map[string]MessageFunc{}
+)
+
+func init() {
+	for tag, message := range builtinMessages {
+		RegisterMessage(tag, message)
+	}
+	for _, rule := range builtinRules {
+		RegisterRule(rule)
+	}
+}
+
+// Validator returns the shared validator with every registered rule,
+// Validate() implementations should use it instead of validator.New()
+func Validator() *validator.Validate {
+	return validate
+}
+
+// ValidateStruct validates v with the shared validator
+func ValidateStruct(v any) error {
+	return validate.Struct(v)
+}
+
+// RegisterRule adds a custom tag to the shared validator. Call it from init, registering
+// a tag twice or a rule without a func panics like a bad regexp would.
+func RegisterRule(rule Rule) {
+	if rule.Tag == "" || rule.Func == nil {
+		panic("validation: rule needs a tag and a func")
+	}
+
+	messagesMu.Lock()
+	_, exists := messages[rule.Tag]
+	messagesMu.Unlock()
+	if exists {
+		panic(fmt.Sprintf("validation: tag %q registered twice", rule.Tag))
+	}
+
+	if err := validate.RegisterValidation(rule.Tag, rule.Func, rule.CallValidationEvenIfNull); err != nil {
+		panic(fmt.Sprintf("validation: failed to register tag %q: %v", rule.Tag, err))
+	}
+
+	message := rule.Message
+	if message == nil {
+		message = defaultMessage
+	}
+	RegisterMessage(rule.Tag, message)
+}
+
+// RegisterMessage sets the message for a tag, useful for validator's built in tags and aliases
+func RegisterMessage(tag string, message MessageFunc) {
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+	messages[tag] = message
+}
+
+// Message builds a MessageFunc from a fixed text, a %s verb is replaced by the tag param
+func Message(format string) MessageFunc {
+	return func(fe validator.FieldError) string {
+		if strings.Contains(format, "%s") {
+			return fmt.Sprintf(format, fe.Param())
+		}
+		return format
+	}
+}
+
+// messageFor looks up the message for a failed tag, translations are keyed validation.<tag>.<kind>
+// (string, collection, number) or validation.<tag> and may use {param} and {field}
+func messageFor(locale language.Tag, fe validator.FieldError) string {
+	params := map[string]string{"param": fe.Param(), "field": fe.Field()}
+	for _, key := range []string{"validation." + fe.Tag() + "." + kindName(fe.Kind()), "validation." + fe.Tag()} {
+		if message, ok := i18n.Lookup(locale, key, params); ok {
+			return message
+		}
+	}
+
+	messagesMu.RLock()
+	message, ok := messages[fe.Tag()]
+	messagesMu.RUnlock()
+
+	if !ok {
+		return defaultMessage(fe)
+	}
+	return message(fe)
+}
+
+func kindName(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "collection"
+	default:
+		return "number"
+	}
+}
+
+func defaultMessage(fe validator.FieldError) string {
+	if fe.Param() != "" {
+		return fmt.Sprintf("failed %s:%s", fe.Tag(), fe.Param())
+	}
+	return fmt.Sprintf("failed %s", fe.Tag())
+}
+
+// lengthMessage words min/max/len for strings and collections differently than for numbers
+func lengthMessage(stringFormat string, collectionFormat string, numberFormat string) MessageFunc {
+	return func(fe validator.FieldError) string {
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf(stringFormat, fe.Param())
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return fmt.Sprintf(collectionFormat, fe.Param())
+		default:
+			return fmt.Sprintf(numberFormat, fe.Param())
+		}
+	}
+}
+
+var builtinMessages = map[string]MessageFunc{
+	"required": Message("is required"),
+	"min": lengthMessage("must be at least %s characters", "must contain at least %s items",
+		"must be at least %s"),
+	"max": lengthMessage("must not exceed %s characters", "must not contain more than %s items",
+		"must not exceed %s"),
+	"len": lengthMessage("must be exactly %s characters", "must contain exactly %s items",
+		"must be exactly %s"),
+	"gt":               Message("must be greater than %s"),
+	"gte":              Message("must be at least %s"),
+	"lt":               Message("must be less than %s"),
+	"lte":              Message("must not exceed %s"),
+	"oneof":            Message("must be one of: %s"),
+	"email":            Message("must be a valid email address"),
+	"url":              Message("must be a valid URL"),
+	"e164":             Message("must be a valid phone number with country code"),
+	"uuid":             Message("must be a valid UUID"),
+	"iso3166_1_alpha2": Message("must be a valid ISO 3166-1 alpha-2 country code"),
+	"dive":             Message("some items are invalid"),
+}
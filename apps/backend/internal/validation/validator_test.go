@@ -0,0 +1,103 @@
+package validation_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Mayank85Y/boil/internal/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	validation.RegisterRule(validation.Rule{
+		Tag:     "even_length",
+		Func:    func(fl validator.FieldLevel) bool { return len(fl.Field().String())%2 == 0 },
+		Message: validation.Message("must have an even number of characters"),
+	})
+	validation.RegisterRule(validation.Rule{
+		Tag:  "no_message",
+		Func: func(fl validator.FieldLevel) bool { return false },
+	})
+	validation.RegisterMessage("alphanum", validation.Message("must contain only letters and numbers"))
+}
+
+func TestMessages(t *testing.T) {
+	type payload struct {
+		Name    string   `json:"name" validate:"min=3"`
+		Tags    []string `json:"tags" validate:"max=1"`
+		Age     int      `json:"age" validate:"gte=18"`
+		Code    string   `json:"code" validate:"len=2"`
+		Kind    string   `json:"kind" validate:"oneof=a b"`
+		Even    string   `json:"even" validate:"even_length"`
+		Other   string   `json:"other" validate:"no_message"`
+		Handle  string   `json:"handle" validate:"alphanum"`
+		Website string   `json:"website" validate:"hostname"`
+	}
+
+	got := fieldErrors(t, &payload{Name: "ab", Tags: []string{"a", "b"}, Age: 17, Code: "abc", Kind: "c",
+		Even: "abc", Handle: "a-b", Website: "not a host"})
+
+	want := map[string]string{
+		"name":    "must be at least 3 characters",
+		"tags":    "must not contain more than 1 items",
+		"age":     "must be at least 18",
+		"code":    "must be exactly 2 characters",
+		"kind":    "must be one of: a b",
+		"even":    "must have an even number of characters",
+		"other":   "failed no_message",
+		"handle":  "must contain only letters and numbers",
+		"website": "failed hostname",
+	}
+	for field, message := range want {
+		assert.Equal(t, message, got[field].Error, field)
+	}
+}
+
+func TestRegisterRulePanics(t *testing.T) {
+	valid := func(fl validator.FieldLevel) bool { return true }
+
+	assert.Panics(t, func() { validation.RegisterRule(validation.Rule{Tag: "even_length", Func: valid}) })
+	assert.Panics(t, func() { validation.RegisterRule(validation.Rule{Tag: "slug", Func: valid}) })
+	assert.Panics(t, func() { validation.RegisterRule(validation.Rule{Func: valid}) })
+	assert.Panics(t, func() { validation.RegisterRule(validation.Rule{Tag: "no_func"}) })
+}
+
+func TestBuiltinRules(t *testing.T) {
+	tomorrow := time.Now().Add(48 * time.Hour)
+
+	tests := []struct {
+		tag   string
+		value any
+		valid bool
+	}{
+		{"slug", "hello-world-2", true},
+		{"slug", "Hello--world", false},
+		{"timezone", "Europe/Berlin", true},
+		{"timezone", "Local", false},
+		{"timezone", "Mars/Olympus", false},
+		{"strong_password", "Sup3r-secret", true},
+		{"strong_password", "Sh0rt!", false},
+		{"strong_password", "nouppercase1!", false},
+		{"country", "de", true},
+		{"country", "XX", false},
+		{"future_date", tomorrow, true},
+		{"future_date", tomorrow.Format(time.RFC3339), true},
+		{"future_date", tomorrow.Format(time.DateOnly), true},
+		{"future_date", time.Now().UTC().Format(time.DateOnly), false},
+		{"future_date", "next week", false},
+		{"uuidList", "", true},
+		{"uuidList", "8f14e45f-ceea-467f-a0f6-5b0e1c7c9b1a, 1b4e28ba-2fa1-41d2-883f-0016d3cca427", true},
+		{"uuidList", "8f14e45f-ceea-467f-a0f6-5b0e1c7c9b1a,nope", false},
+	}
+
+	for _, tt := range tests {
+		err := validation.Validator().Var(tt.value, tt.tag)
+		assert.Equal(t, tt.valid, err == nil, "%s %v", tt.tag, tt.value)
+	}
+}
+
+func TestValidateIgnoresNonStructs(t *testing.T) {
+	assert.NoError(t, validation.Validate(context.Background(), "not a struct"))
+}
@@ -1,10 +1,18 @@
 
-import { extendZodWithOpenApi } from "@anatine/zod-openapi";
+import { extendZodWithOpenApi, generateSchema } from "@anatine/zod-openapi";
 import { z } from "zod";
 
 extendZodWithOpenApi(z);
 import { generateOpenApi } from "@ts-rest/open-api";
 import { apiContract } from "./contracts/index.js";
+import {
+  ErrorCatalog,
+  ZErrorAction,
+  ZErrorCode,
+  ZErrorDebug,
+  ZFieldError,
+  ZHTTPError,
+} from "@boil/zod";
 
 
 type SecurityRequirementObject = {
@@ -56,6 +64,17 @@ export const OpenAPI = Object.assign(
   ),
   {
     components: {
+      // keep in sync with apps/backend/cmd/errcodes which writes the same schemas
+      schemas: {
+        ErrorCode: {
+          ...generateSchema(ZErrorCode),
+          "x-error-catalog": ErrorCatalog,
+        },
+        FieldError: generateSchema(ZFieldError),
+        ErrorAction: generateSchema(ZErrorAction),
+        ErrorDebug: generateSchema(ZErrorDebug),
+        HTTPError: generateSchema(ZHTTPError),
+      },
       securitySchemes: {
         bearerAuth: {
           type: "http",
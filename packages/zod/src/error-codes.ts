@@ -0,0 +1,251 @@
+// Code generated by apps/backend/cmd/errcodes. DO NOT EDIT.
+
+import { z } from "zod";
+
+export const ErrorCatalog = [
+  {
+    code: "BAD_GATEWAY",
+    status: 502,
+    description: "An upstream service returned an invalid response",
+    overridable: false,
+  },
+  {
+    code: "BAD_REQUEST",
+    status: 400,
+    description: "The request is malformed or failed validation",
+    overridable: false,
+  },
+  {
+    code: "CONFLICT",
+    status: 409,
+    description: "The request conflicts with the current state of the resource",
+    overridable: false,
+  },
+  {
+    code: "FORBIDDEN",
+    status: 403,
+    description: "The caller is not allowed to perform this action",
+    overridable: false,
+  },
+  {
+    code: "GATEWAY_TIMEOUT",
+    status: 504,
+    description: "An upstream service did not respond in time",
+    overridable: false,
+  },
+  {
+    code: "GONE",
+    status: 410,
+    description: "The resource is no longer available",
+    overridable: false,
+  },
+  {
+    code: "IDEMPOTENCY_KEY_INVALID",
+    status: 400,
+    description: "The Idempotency-Key header is too long or contains invalid characters",
+    overridable: true,
+  },
+  {
+    code: "IDEMPOTENCY_KEY_IN_USE",
+    status: 409,
+    description: "A request with the same Idempotency-Key is still in progress, retry the request",
+    overridable: true,
+  },
+  {
+    code: "IDEMPOTENCY_KEY_REQUIRED",
+    status: 400,
+    description: "The route requires an Idempotency-Key header",
+    overridable: true,
+  },
+  {
+    code: "IDEMPOTENCY_KEY_REUSED",
+    status: 422,
+    description: "The Idempotency-Key was already used for a different request",
+    overridable: true,
+  },
+  {
+    code: "INTERNAL_SERVER_ERROR",
+    status: 500,
+    description: "An unexpected error occurred",
+    overridable: false,
+  },
+  {
+    code: "INVALID_DATA",
+    status: 400,
+    description: "A value was rejected by the database",
+    overridable: true,
+  },
+  {
+    code: "INVALID_FORMAT",
+    status: 400,
+    description: "A value could not be parsed into its column type",
+    overridable: true,
+  },
+  {
+    code: "METHOD_NOT_ALLOWED",
+    status: 405,
+    description: "The route does not support this HTTP method",
+    overridable: false,
+  },
+  {
+    code: "NOT_ACCEPTABLE",
+    status: 406,
+    description: "None of the requested representations are available",
+    overridable: false,
+  },
+  {
+    code: "NOT_FOUND",
+    status: 404,
+    description: "The route or resource does not exist",
+    overridable: false,
+  },
+  {
+    code: "NOT_IMPLEMENTED",
+    status: 501,
+    description: "The functionality is not implemented",
+    overridable: false,
+  },
+  {
+    code: "PRECONDITION_FAILED",
+    status: 412,
+    description: "A request precondition such as If-Match failed",
+    overridable: false,
+  },
+  {
+    code: "QUERY_TIMEOUT",
+    status: 503,
+    description: "The database query took too long",
+    overridable: true,
+  },
+  {
+    code: "REQUEST_ENTITY_TOO_LARGE",
+    status: 413,
+    description: "The request body exceeds the allowed size",
+    overridable: false,
+  },
+  {
+    code: "REQUEST_TIMEOUT",
+    status: 408,
+    description: "The request took too long to be received",
+    overridable: false,
+  },
+  {
+    code: "RESOURCE_LOCKED",
+    status: 409,
+    description: "The resource is locked by another request, retry the request",
+    overridable: true,
+  },
+  {
+    code: "SERVICE_UNAVAILABLE",
+    status: 503,
+    description: "The service is temporarily unavailable",
+    overridable: true,
+  },
+  {
+    code: "TOO_MANY_REQUESTS",
+    status: 429,
+    description: "Rate limit exceeded",
+    overridable: false,
+  },
+  {
+    code: "TRANSACTION_CONFLICT",
+    status: 409,
+    description: "A concurrent update caused the transaction to fail, retry the request",
+    overridable: true,
+  },
+  {
+    code: "UNAUTHORIZED",
+    status: 401,
+    description: "Authentication is missing or invalid",
+    overridable: false,
+  },
+  {
+    code: "UNPROCESSABLE_ENTITY",
+    status: 422,
+    description: "The request is well formed but semantically invalid",
+    overridable: false,
+  },
+  {
+    code: "UNSUPPORTED_MEDIA_TYPE",
+    status: 415,
+    description: "The request content type is not supported",
+    overridable: false,
+  },
+  {
+    code: "VALUE_TOO_LONG",
+    status: 400,
+    description: "A value is too long for its column",
+    overridable: true,
+  },
+  {
+    code: "{ENTITY}_ALREADY_EXISTS",
+    status: 400,
+    description: "A unique constraint on the entity was violated",
+    overridable: true,
+  },
+  {
+    code: "{ENTITY}_CONFLICT",
+    status: 409,
+    description: "An exclusion constraint on the entity was violated",
+    overridable: true,
+  },
+  {
+    code: "{ENTITY}_INVALID",
+    status: 400,
+    description: "A check constraint on the entity was violated",
+    overridable: true,
+  },
+  {
+    code: "{ENTITY}_NOT_FOUND",
+    status: 404,
+    description: "The requested entity does not exist",
+    overridable: true,
+  },
+  {
+    code: "{ENTITY}_REQUIRED",
+    status: 400,
+    description: "A required column of the entity is missing",
+    overridable: true,
+  },
+] as const;
+
+export const ZErrorCode = z.union([
+  z.enum([
+    "BAD_GATEWAY",
+    "BAD_REQUEST",
+    "CONFLICT",
+    "FORBIDDEN",
+    "GATEWAY_TIMEOUT",
+    "GONE",
+    "IDEMPOTENCY_KEY_INVALID",
+    "IDEMPOTENCY_KEY_IN_USE",
+    "IDEMPOTENCY_KEY_REQUIRED",
+    "IDEMPOTENCY_KEY_REUSED",
+    "INTERNAL_SERVER_ERROR",
+    "INVALID_DATA",
+    "INVALID_FORMAT",
+    "METHOD_NOT_ALLOWED",
+    "NOT_ACCEPTABLE",
+    "NOT_FOUND",
+    "NOT_IMPLEMENTED",
+    "PRECONDITION_FAILED",
+    "QUERY_TIMEOUT",
+    "REQUEST_ENTITY_TOO_LARGE",
+    "REQUEST_TIMEOUT",
+    "RESOURCE_LOCKED",
+    "SERVICE_UNAVAILABLE",
+    "TOO_MANY_REQUESTS",
+    "TRANSACTION_CONFLICT",
+    "UNAUTHORIZED",
+    "UNPROCESSABLE_ENTITY",
+    "UNSUPPORTED_MEDIA_TYPE",
+    "VALUE_TOO_LONG",
+  ]),
+  z.string().regex(/^[A-Z][A-Z0-9]*(?:_[A-Z0-9]+)*_ALREADY_EXISTS$/),
+  z.string().regex(/^[A-Z][A-Z0-9]*(?:_[A-Z0-9]+)*_CONFLICT$/),
+  z.string().regex(/^[A-Z][A-Z0-9]*(?:_[A-Z0-9]+)*_INVALID$/),
+  z.string().regex(/^[A-Z][A-Z0-9]*(?:_[A-Z0-9]+)*_NOT_FOUND$/),
+  z.string().regex(/^[A-Z][A-Z0-9]*(?:_[A-Z0-9]+)*_REQUIRED$/),
+]);
+
+export type ErrorCode = z.infer<typeof ZErrorCode>;
@@ -0,0 +1,80 @@
+import { z } from "zod";
+import { ZErrorCode } from "./error-codes.js";
+
+export const ZFieldError = z.object({
+  field: z.string(),
+  error: z.string(),
+  location: z.enum(["body", "query", "path"]).optional(),
+});
+
+const actionFields = {
+  message: z.string(),
+  value: z.string(),
+};
+
+export const ZErrorAction = z.discriminatedUnion("type", [
+  z.object({
+    ...actionFields,
+    type: z.literal("redirect"),
+    payload: z.object({ url: z.string() }).optional(),
+  }),
+  z.object({
+    ...actionFields,
+    type: z.literal("reauthenticate"),
+    payload: z.object({ reason: z.string().optional() }).optional(),
+  }),
+  z.object({
+    ...actionFields,
+    type: z.literal("retry"),
+    payload: z.object({ afterSeconds: z.number().int() }).optional(),
+  }),
+  z.object({
+    ...actionFields,
+    type: z.literal("upgrade_plan"),
+    payload: z
+      .object({
+        requiredPlan: z.string(),
+        feature: z.string().optional(),
+        url: z.string().optional(),
+      })
+      .optional(),
+  }),
+  z.object({
+    ...actionFields,
+    type: z.literal("refresh"),
+    payload: z
+      .object({ resource: z.string(), id: z.string().optional() })
+      .optional(),
+  }),
+  z.object({
+    ...actionFields,
+    type: z.literal("contact_support"),
+    payload: z
+      .object({
+        email: z.string().optional(),
+        url: z.string().optional(),
+        reference: z.string().optional(),
+      })
+      .optional(),
+  }),
+]);
+
+export type ErrorAction = z.infer<typeof ZErrorAction>;
+
+export const ZErrorDebug = z.object({
+  chain: z.array(z.string()),
+  stack: z.string().optional(),
+});
+
+export const ZHTTPError = z.object({
+  code: ZErrorCode,
+  message: z.string(),
+  status: z.number().int(),
+  override: z.boolean(),
+  errors: z.array(ZFieldError).nullable(),
+  actions: ZErrorAction.nullable(),
+  details: z.record(z.unknown()).optional(),
+  debug: ZErrorDebug.optional(),
+});
+
+export type HTTPError = z.infer<typeof ZHTTPError>;
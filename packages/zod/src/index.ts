@@ -4,4 +4,6 @@ import { z } from "zod";
 extendZodWithOpenApi(z);
 
 export * from "./utils.js";
-export * from "./health.js";
\ No newline at end of file
+export * from "./health.js";
+export * from "./error-codes.js";
+export * from "./errors.js";
\ No newline at end of file